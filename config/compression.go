@@ -0,0 +1,28 @@
+package config
+
+import (
+	"compress/gzip"
+	"log"
+)
+
+// CompressionConfig define cuándo y con qué nivel se comprimen las respuestas.
+type CompressionConfig struct {
+	// MinBytes es el tamaño desde el cual se comprime una respuesta; las más pequeñas se envían
+	// tal cual porque el ahorro no compensa el costo.
+	MinBytes int
+	// Level es el nivel de gzip, de 1 (más rápido) a 9 (mayor compresión); -1 usa el nivel por defecto.
+	Level int
+}
+
+// LoadCompressionConfig lee la configuración de compresión desde las variables de entorno.
+func LoadCompressionConfig() CompressionConfig {
+	level := getEnvInt("COMPRESSION_LEVEL", gzip.DefaultCompression)
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		log.Printf("⚠️ Valor inválido para COMPRESSION_LEVEL (%d), usando %d", level, gzip.DefaultCompression)
+		level = gzip.DefaultCompression
+	}
+	return CompressionConfig{
+		MinBytes: getEnvInt("COMPRESSION_MIN_BYTES", 1024),
+		Level:    level,
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getEnvInt lee una variable de entorno entera, usando def si no existe o es inválida.
+func getEnvInt(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("⚠️ Valor inválido para %s (%q), usando %d", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// getEnvBool lee una variable de entorno booleana, usando def si no existe o es inválida.
+func getEnvBool(key string, def bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Printf("⚠️ Valor inválido para %s (%q), usando %t", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// getEnvDuration lee una variable de entorno con formato time.Duration (ej. "30s"), usando def si no existe o es inválida.
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("⚠️ Valor inválido para %s (%q), usando %s", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// getEnvList lee una variable de entorno con valores separados por comas, ignorando los vacíos.
+// Usa def si la variable no existe.
+func getEnvList(key string, def []string) []string {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+)
+
+// MaintenanceConfig define el estado inicial del modo mantenimiento.
+type MaintenanceConfig struct {
+	Enabled bool
+	// Block indica qué peticiones se rechazan durante el mantenimiento: "writes" o "reads".
+	Block string
+	// AllowedIPs son las IPs (por ejemplo, de los administradores) que nunca se bloquean.
+	AllowedIPs []string
+}
+
+// LoadMaintenanceConfig lee el modo mantenimiento desde las variables de entorno.
+func LoadMaintenanceConfig() MaintenanceConfig {
+	block := os.Getenv("MAINTENANCE_BLOCK")
+	if block == "" {
+		block = "writes"
+	}
+
+	return MaintenanceConfig{
+		Enabled:    getEnvBool("MAINTENANCE_ENABLED", false),
+		Block:      block,
+		AllowedIPs: getEnvList("MAINTENANCE_ALLOWED_IPS", nil),
+	}
+}
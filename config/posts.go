@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// PostConfig agrupa los parámetros usados al presentar las publicaciones.
+type PostConfig struct {
+	// ReadingWPM son las palabras por minuto con que se estima el tiempo de lectura.
+	ReadingWPM int
+	// ExpirySweepInterval es cada cuánto se buscan y eliminan las publicaciones vencidas.
+	ExpirySweepInterval time.Duration
+	// LinkPreviewTimeout es el tiempo máximo para descargar la página de un enlace al generar su vista previa.
+	LinkPreviewTimeout time.Duration
+	// RejectSlugConflicts responde 409 cuando el slug elegido por el autor ya está en uso, en
+	// lugar de asignar uno generado.
+	RejectSlugConflicts bool
+	// DailyPostLimit es el máximo de publicaciones por usuario en 24 horas; 0 desactiva el límite.
+	DailyPostLimit int
+	// BlockedDomains son los dominios (con sus subdominios) a los que no se permite enlazar.
+	BlockedDomains []string
+	// BlockedDomainsMode es "reject" para rechazar la publicación o "flag" para guardarla
+	// marcada como reportada.
+	BlockedDomainsMode string
+}
+
+// LoadPostConfig lee la configuración de las publicaciones desde las variables de entorno.
+func LoadPostConfig() PostConfig {
+	wpm := getEnvInt("READING_WPM", 200)
+	if wpm <= 0 {
+		wpm = 200
+	}
+	return PostConfig{
+		ReadingWPM:          wpm,
+		ExpirySweepInterval: getEnvDuration("POST_EXPIRY_INTERVAL", time.Minute),
+		LinkPreviewTimeout:  getEnvDuration("LINK_PREVIEW_TIMEOUT", 5*time.Second),
+		RejectSlugConflicts: getEnvBool("POST_SLUG_CONFLICT_REJECT", false),
+		DailyPostLimit:      getEnvInt("POST_DAILY_LIMIT", 50),
+		BlockedDomains:      loadBlockedDomains(),
+		BlockedDomainsMode:  blockedDomainsMode(),
+	}
+}
+
+// loadBlockedDomains une los dominios de POST_BLOCKED_DOMAINS (separados por comas) con los del
+// archivo POST_BLOCKED_DOMAINS_FILE, uno por línea; las líneas vacías o que empiezan con "#" se
+// ignoran. Si el archivo no se puede leer se usa solo la variable.
+func loadBlockedDomains() []string {
+	domains := getEnvList("POST_BLOCKED_DOMAINS", nil)
+
+	path := os.Getenv("POST_BLOCKED_DOMAINS_FILE")
+	if path == "" {
+		return domains
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("⚠️ No se pudo leer POST_BLOCKED_DOMAINS_FILE (%s): %v", path, err)
+		return domains
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			domains = append(domains, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("⚠️ Error leyendo POST_BLOCKED_DOMAINS_FILE (%s): %v", path, err)
+	}
+	return domains
+}
+
+// blockedDomainsMode lee POST_BLOCKED_DOMAINS_MODE, usando "reject" si no existe o es inválido.
+func blockedDomainsMode() string {
+	mode := os.Getenv("POST_BLOCKED_DOMAINS_MODE")
+	switch mode {
+	case "reject", "flag":
+		return mode
+	case "":
+		return "reject"
+	}
+	log.Printf("⚠️ Valor inválido para POST_BLOCKED_DOMAINS_MODE (%q), usando reject", mode)
+	return "reject"
+}
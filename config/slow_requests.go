@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// SlowRequestConfig define los umbrales a partir de los cuales una petición se registra como
+// lenta. Las subidas de imágenes y las rutas de administración tienen su propio umbral porque
+// son lentas por naturaleza. Un umbral de 0 desactiva el registro para ese grupo.
+type SlowRequestConfig struct {
+	Default time.Duration
+	Uploads time.Duration
+	Admin   time.Duration
+}
+
+// LoadSlowRequestConfig lee los umbrales de peticiones lentas desde las variables de entorno.
+func LoadSlowRequestConfig() SlowRequestConfig {
+	return SlowRequestConfig{
+		Default: getEnvDuration("SLOW_REQUEST_THRESHOLD", time.Second),
+		Uploads: getEnvDuration("SLOW_REQUEST_THRESHOLD_UPLOADS", 15*time.Second),
+		Admin:   getEnvDuration("SLOW_REQUEST_THRESHOLD_ADMIN", 30*time.Second),
+	}
+}
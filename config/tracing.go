@@ -0,0 +1,35 @@
+package config
+
+import "os"
+
+// TracingConfig controla el envío de trazas OpenTelemetry. Está desactivado por defecto.
+type TracingConfig struct {
+	Enabled bool
+	// Endpoint es el host:puerto del colector OTLP/HTTP.
+	Endpoint string
+	// Insecure envía las trazas por HTTP sin TLS, por ejemplo a un colector local.
+	Insecure    bool
+	ServiceName string
+	// SampleRatio es la fracción de trazas nuevas que se registran, entre 0 y 1.
+	SampleRatio float64
+}
+
+// LoadTracingConfig lee la configuración de trazado desde las variables de entorno.
+func LoadTracingConfig() TracingConfig {
+	endpoint := os.Getenv("TRACING_OTLP_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "localhost:4318"
+	}
+	serviceName := os.Getenv("TRACING_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "talkus-backend"
+	}
+
+	return TracingConfig{
+		Enabled:     getEnvBool("TRACING_ENABLED", false),
+		Endpoint:    endpoint,
+		Insecure:    getEnvBool("TRACING_INSECURE", false),
+		ServiceName: serviceName,
+		SampleRatio: float64(getEnvInt("TRACING_SAMPLE_PERCENT", 100)) / 100,
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// UploadConfig agrupa los límites aplicados a las imágenes subidas.
+type UploadConfig struct {
+	// MaxRequestBytes es el tamaño máximo del cuerpo multipart completo.
+	MaxRequestBytes int64
+	// MultipartMemoryBytes es cuánto del formulario se mantiene en memoria; el resto
+	// de los archivos se escribe en disco temporal mientras se procesa la petición.
+	MultipartMemoryBytes int64
+
+	MaxImageWidth  int
+	MaxImageHeight int
+	// AllowedExtensions son las extensiones de archivo aceptadas, sin el punto y en minúsculas.
+	// La extensión también debe coincidir con el formato real de la imagen.
+	AllowedExtensions []string
+	// ConvertToWebP guarda las imágenes en WebP, conservando una URL JPEG de respaldo.
+	ConvertToWebP bool
+	// UploadTimeout es el tiempo máximo de cada subida a Cloudinary, independiente del plazo
+	// de la petición.
+	UploadTimeout time.Duration
+	// DeferOnFailure crea la publicación sin imagen cuando Cloudinary no está disponible y
+	// reintenta la subida en segundo plano cada RetryInterval.
+	DeferOnFailure bool
+	RetryInterval  time.Duration
+	// AllowedFolders son las carpetas de Cloudinary en las que se permite subir imágenes.
+	AllowedFolders []string
+	// StreamUploads envía la imagen de una publicación a Cloudinary mientras se lee del cuerpo
+	// de la petición, sin guardarla antes en memoria ni en disco. La imagen debe ser el último
+	// campo del formulario y, como no puede volver a leerse, DeferOnFailure no se aplica.
+	StreamUploads bool
+	// PreviewTTL es el tiempo que se conservan las imágenes subidas para previsualización.
+	PreviewTTL time.Duration
+	// PreviewSweepInterval es cada cuánto se eliminan las previsualizaciones con más de
+	// PreviewTTL, incluidas las que quedaron pendientes al reiniciarse el servidor.
+	PreviewSweepInterval time.Duration
+}
+
+// LoadUploadConfig lee los límites de subida desde las variables de entorno.
+func LoadUploadConfig() UploadConfig {
+	return UploadConfig{
+		MaxRequestBytes:      int64(getEnvInt("UPLOAD_MAX_REQUEST_MB", 20)) << 20,
+		MultipartMemoryBytes: int64(getEnvInt("UPLOAD_MULTIPART_MEMORY_MB", 10)) << 20,
+		MaxImageWidth:        getEnvInt("UPLOAD_MAX_IMAGE_WIDTH", 4096),
+		MaxImageHeight:       getEnvInt("UPLOAD_MAX_IMAGE_HEIGHT", 4096),
+		AllowedExtensions:    normalizeExtensions(getEnvList("UPLOAD_ALLOWED_EXTENSIONS", []string{"jpg", "jpeg", "png", "gif", "webp"})),
+		ConvertToWebP:        getEnvBool("UPLOAD_CONVERT_WEBP", false),
+		UploadTimeout:        getEnvDuration("UPLOAD_TIMEOUT", time.Minute),
+		DeferOnFailure:       getEnvBool("UPLOAD_DEFER_ON_FAILURE", false),
+		RetryInterval:        getEnvDuration("UPLOAD_RETRY_INTERVAL", time.Minute),
+		AllowedFolders:       getEnvList("UPLOAD_ALLOWED_FOLDERS", []string{"posts_images", "previews"}),
+		PreviewTTL:           getEnvDuration("UPLOAD_PREVIEW_TTL", 30*time.Minute),
+		StreamUploads:        getEnvBool("UPLOAD_STREAM", false),
+		PreviewSweepInterval: getEnvDuration("UPLOAD_PREVIEW_SWEEP_INTERVAL", 10*time.Minute),
+	}
+}
+
+// normalizeExtensions quita el punto inicial y pasa a minúsculas cada extensión, para aceptar
+// tanto "JPG" como ".jpg" en la configuración.
+func normalizeExtensions(exts []string) []string {
+	normalized := make([]string, 0, len(exts))
+	for _, ext := range exts {
+		if ext = strings.ToLower(strings.TrimPrefix(ext, ".")); ext != "" {
+			normalized = append(normalized, ext)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// ViewConfig agrupa los parámetros del conteo de vistas de las publicaciones.
+type ViewConfig struct {
+	// DebounceWindow es el tiempo durante el cual las vistas repetidas de un mismo
+	// visitante a una publicación cuentan una sola vez.
+	DebounceWindow time.Duration
+	// FlushInterval es cada cuánto se escriben en Firestore las vistas acumuladas en memoria.
+	FlushInterval time.Duration
+}
+
+// LoadViewConfig lee la configuración del conteo de vistas desde las variables de entorno.
+func LoadViewConfig() ViewConfig {
+	return ViewConfig{
+		DebounceWindow: getEnvDuration("VIEW_DEBOUNCE_WINDOW", 30*time.Minute),
+		FlushInterval:  getEnvDuration("VIEW_FLUSH_INTERVAL", 10*time.Second),
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// WebhookConfig agrupa la verificación de las notificaciones que envía Cloudinary.
+type WebhookConfig struct {
+	// CloudinarySecret es el API secret con el que Cloudinary firma las notificaciones.
+	CloudinarySecret string
+	// CloudinarySignatureAlgorithm es sha1 o sha256, según la configuración de la cuenta.
+	CloudinarySignatureAlgorithm string
+	// CloudinaryMaxAge es la antigüedad máxima de una notificación; las más viejas se rechazan
+	// para que no puedan reenviarse.
+	CloudinaryMaxAge time.Duration
+}
+
+// LoadWebhookConfig lee la configuración de las notificaciones desde las variables de entorno.
+func LoadWebhookConfig() WebhookConfig {
+	algorithm := strings.ToLower(os.Getenv("CLOUDINARY_SIGNATURE_ALGORITHM"))
+	if algorithm == "" {
+		algorithm = "sha1"
+	}
+
+	return WebhookConfig{
+		CloudinarySecret:             os.Getenv("CLOUDINARY_API_SECRET"),
+		CloudinarySignatureAlgorithm: algorithm,
+		CloudinaryMaxAge:             getEnvDuration("CLOUDINARY_WEBHOOK_MAX_AGE", 2*time.Hour),
+	}
+}
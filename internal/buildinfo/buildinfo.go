@@ -0,0 +1,42 @@
+// Package buildinfo expone la versión y el commit con los que se compiló el servidor.
+//
+// Los valores se fijan al compilar con -ldflags, por ejemplo:
+//
+//	go build -ldflags "-X github.com/JuanPidarraga/talkus-backend/internal/buildinfo.Version=1.4.0 \
+//	  -X github.com/JuanPidarraga/talkus-backend/internal/buildinfo.Commit=$(git rev-parse --short HEAD)"
+package buildinfo
+
+import "runtime/debug"
+
+// Version y Commit se sobrescriben con -ldflags al compilar.
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// Info describe la compilación en ejecución.
+type Info struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// Get retorna la información de la compilación. Si Commit no se fijó al compilar, se toma la
+// revisión que Go registra al compilar dentro de un repositorio git, o "unknown".
+func Get() Info {
+	commit := Commit
+	if commit == "" {
+		commit = vcsRevision()
+	}
+	return Info{Version: Version, Commit: commit}
+}
+
+func vcsRevision() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" && s.Value != "" {
+				return s.Value
+			}
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// minPurgeSize es a partir de cuántas entradas Set empieza a eliminar las vencidas.
+const minPurgeSize = 64
+
+// TTLCache es una caché en memoria con expiración por entrada, segura para uso concurrente.
+// Las entradas vencidas se eliminan cuando la caché duplica su tamaño desde la última limpieza,
+// de modo que el costo de recorrerla se reparte entre las escrituras.
+type TTLCache[V any] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]entry[V]
+	// nextPurge es el tamaño con el que Set vuelve a eliminar las entradas vencidas.
+	nextPurge int
+}
+
+// NewTTLCache crea una caché cuyas entradas expiran después de ttl.
+func NewTTLCache[V any](ttl time.Duration) *TTLCache[V] {
+	return &TTLCache[V]{
+		ttl:       ttl,
+		entries:   make(map[string]entry[V]),
+		nextPurge: minPurgeSize,
+	}
+}
+
+// Get retorna el valor asociado a key si existe y no ha expirado.
+func (c *TTLCache[V]) Get(key string) (V, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set guarda value bajo key con el TTL de la caché.
+func (c *TTLCache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value)
+}
+
+// SetIfAbsent guarda value bajo key solo si no hay una entrada vigente, y retorna si lo guardó.
+// La comprobación y la escritura son atómicas: de varias llamadas concurrentes con la misma key
+// solo una retorna true.
+func (c *TTLCache[V]) SetIfAbsent(key string, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok && !time.Now().After(e.expiresAt) {
+		return false
+	}
+	c.set(key, value)
+	return true
+}
+
+// set guarda la entrada y, si la caché alcanzó nextPurge, elimina las vencidas. Debe llamarse
+// con el lock tomado.
+func (c *TTLCache[V]) set(key string, value V) {
+	if len(c.entries) >= c.nextPurge {
+		c.purgeExpired()
+		c.nextPurge = max(2*len(c.entries), minPurgeSize)
+	}
+	c.entries[key] = entry[V]{value: value, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// Delete elimina la entrada asociada a key.
+func (c *TTLCache[V]) Delete(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// purgeExpired elimina las entradas vencidas. Debe llamarse con el lock tomado.
+func (c *TTLCache[V]) purgeExpired() {
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+}
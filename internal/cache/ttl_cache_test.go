@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetIfAbsentStoresOnce(t *testing.T) {
+	c := NewTTLCache[bool](time.Minute)
+
+	var stored atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.SetIfAbsent("post|viewer", true) {
+				stored.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := stored.Load(); n != 1 {
+		t.Fatalf("SetIfAbsent guardó %d veces, se esperaba 1", n)
+	}
+}
+
+func TestSetIfAbsentReplacesExpired(t *testing.T) {
+	c := NewTTLCache[bool](time.Millisecond)
+	c.SetIfAbsent("k", true)
+	time.Sleep(5 * time.Millisecond)
+
+	if !c.SetIfAbsent("k", true) {
+		t.Fatal("SetIfAbsent no reemplazó una entrada vencida")
+	}
+}
+
+func TestSetPurgesExpiredEntriesAsItGrows(t *testing.T) {
+	c := NewTTLCache[int](time.Millisecond)
+	for i := range minPurgeSize {
+		c.Set(fmt.Sprint("viejo", i), i)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	c.Set("nuevo", 0)
+	if n := len(c.entries); n != 1 {
+		t.Fatalf("quedaron %d entradas, se esperaba solo la nueva", n)
+	}
+	// la siguiente limpieza espera a que la caché vuelva a crecer
+	if c.nextPurge != minPurgeSize {
+		t.Fatalf("nextPurge = %d, se esperaba %d", c.nextPurge, minPurgeSize)
+	}
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+// AuditController expone el log de auditoría de moderación.
+type AuditController struct {
+	usecase *usecases.AuditUsecase
+}
+
+// NewAuditController crea un nuevo controlador del log de auditoría.
+func NewAuditController(usecase *usecases.AuditUsecase) *AuditController {
+	return &AuditController{usecase: usecase}
+}
+
+// @Summary Consultar el log de auditoría
+// @Description Lista las acciones de moderación de la más reciente a la más antigua, con filtros opcionales por actor, acción y rango de fechas (YYYY-MM-DD, "to" exclusivo). Solo para administradores.
+// @Tags Admin
+// @Produce json
+// @Param actor query string false "ID del moderador que ejecutó la acción"
+// @Param action query string false "Acción (flag, unflag, delete, ban, transfer)"
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final exclusiva (YYYY-MM-DD)"
+// @Param limit query int false "Resultados por página (por defecto 50, máximo 200)"
+// @Param offset query int false "Número de resultados a omitir"
+// @Success 200 {array} models.AuditLog "Entradas del log de auditoría"
+// @Header 200 {string} Link "Enlaces a las páginas first, prev, next y last (RFC 8288)"
+// @Header 200 {int} X-Total-Count "Total de entradas que cumplen los filtros"
+// @Failure 400 {object} map[string]string "Parámetros inválidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/audit-logs [get]
+func (c *AuditController) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.AuditLogFilter{
+		ActorID: q.Get("actor"),
+		Action:  q.Get("action"),
+	}
+
+	var err error
+	filter.Limit, filter.Offset, err = parsePagination(r, 50, maxPageSize)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if raw := q.Get("from"); raw != "" {
+		if filter.From, err = time.Parse(time.DateOnly, raw); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'from' debe tener formato YYYY-MM-DD")
+			return
+		}
+	}
+	if raw := q.Get("to"); raw != "" {
+		if filter.To, err = time.Parse(time.DateOnly, raw); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'to' debe tener formato YYYY-MM-DD")
+			return
+		}
+	}
+
+	entries, total, err := c.usecase.List(r.Context(), filter)
+	if err != nil {
+		log.Printf("Error obteniendo log de auditoría: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	writePaginationHeaders(w, r, filter.Limit, filter.Offset, total)
+
+	httputil.WriteJSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// BlockController maneja los bloqueos entre usuarios.
+type BlockController struct {
+	usecase *usecases.BlockUsecase
+}
+
+// NewBlockController crea un nuevo controlador de bloqueos.
+func NewBlockController(usecase *usecases.BlockUsecase) *BlockController {
+	return &BlockController{usecase: usecase}
+}
+
+// @Summary Bloquear a un usuario
+// @Description Bloquea al usuario indicado en nombre del usuario autenticado. A diferencia de silenciar, afecta a ambos: ninguno ve las publicaciones del otro en ninguna lectura (listados, búsquedas por etiqueta, sugerencias, relacionadas, top, archivo, feeds, vista previa y al abrirlas), y no pueden reaccionar a ellas ni compartirlas (403). Las reacciones y reposts previos no se eliminan.
+// @Tags User
+// @Param id path string true "ID del usuario a bloquear"
+// @Success 204 "Usuario bloqueado"
+// @Failure 400 {object} map[string]string "No puedes bloquearte a ti mismo"
+// @Failure 401 {object} map[string]string "No autenticado"
+// @Failure 404 {object} map[string]string "Usuario no encontrado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/block [post]
+func (c *BlockController) Block(w http.ResponseWriter, r *http.Request) {
+	err := c.usecase.Block(r.Context(), viewerID(r), mux.Vars(r)["id"])
+	switch {
+	case errors.Is(err, usecases.ErrSelfBlock):
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrUserNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Usuario no encontrado")
+	case err != nil:
+		log.Printf("Error bloqueando usuario: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary Desbloquear a un usuario
+// @Description Quita el bloqueo del usuario autenticado al usuario indicado. No falla si no existía. Si el otro usuario también lo bloqueó, ese bloqueo se mantiene.
+// @Tags User
+// @Param id path string true "ID del usuario bloqueado"
+// @Success 204 "Usuario desbloqueado"
+// @Failure 401 {object} map[string]string "No autenticado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/block [delete]
+func (c *BlockController) Unblock(w http.ResponseWriter, r *http.Request) {
+	if err := c.usecase.Unblock(r.Context(), viewerID(r), mux.Vars(r)["id"]); err != nil {
+		log.Printf("Error desbloqueando usuario: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
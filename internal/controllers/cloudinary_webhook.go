@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+// maxNotificationBytes limita el cuerpo de las notificaciones de Cloudinary, que se lee completo
+// para verificar su firma.
+const maxNotificationBytes = 1 << 20
+
+// CloudinaryWebhookController recibe las notificaciones asíncronas de Cloudinary.
+type CloudinaryWebhookController struct {
+	verifier   *service.NotificationVerifier
+	moderation *usecases.ModerationUsecase
+}
+
+// NewCloudinaryWebhookController crea un nuevo controlador de notificaciones de Cloudinary.
+func NewCloudinaryWebhookController(verifier *service.NotificationVerifier, moderation *usecases.ModerationUsecase) *CloudinaryWebhookController {
+	return &CloudinaryWebhookController{verifier: verifier, moderation: moderation}
+}
+
+// CloudinaryNotification son los campos que se usan de una notificación de Cloudinary, con los
+// nombres en snake_case que define Cloudinary.
+type CloudinaryNotification struct {
+	NotificationType string `json:"notification_type"`
+	PublicID         string `json:"public_id"`
+	ModerationStatus string `json:"moderation_status"`
+}
+
+// @Summary Recibir una notificación de Cloudinary
+// @Description Verifica la firma de la notificación (cabeceras X-Cld-Timestamp y X-Cld-Signature, con el API secret) y la aplica. Las de moderación guardan el resultado en imageModeration de la publicación que usa la imagen y, si fue rechazada, la marcan como reportada. Las demás notificaciones, y las de imágenes que no pertenecen a ninguna publicación, se aceptan sin cambios.
+// @Tags Webhooks
+// @Accept json
+// @Param X-Cld-Timestamp header string true "Fecha de la notificación (segundos Unix)"
+// @Param X-Cld-Signature header string true "Firma de la notificación"
+// @Success 204 "Notificación procesada"
+// @Failure 400 {object} map[string]string "Cuerpo inválido"
+// @Failure 401 {object} map[string]string "Firma inválida o notificación expirada"
+// @Failure 413 {object} map[string]string "Cuerpo demasiado grande"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /webhooks/cloudinary [post]
+func (c *CloudinaryWebhookController) Notify(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxNotificationBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		httputil.WriteError(w, http.StatusRequestEntityTooLarge, "La notificación es demasiado grande")
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Cuerpo inválido")
+		return
+	}
+
+	if err := c.verifier.Verify(body, r.Header.Get("X-Cld-Timestamp"), r.Header.Get("X-Cld-Signature")); err != nil {
+		log.Printf("Notificación de Cloudinary rechazada: %v", err)
+		httputil.WriteError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var n CloudinaryNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Cuerpo inválido")
+		return
+	}
+
+	if n.NotificationType == "moderation" && n.PublicID != "" &&
+		(n.ModerationStatus == usecases.ImageModerationApproved || n.ModerationStatus == usecases.ImageModerationRejected) {
+		err := c.moderation.ApplyImageModeration(r.Context(), n.PublicID, n.ModerationStatus)
+		switch {
+		case errors.Is(err, usecases.ErrPostNotFound):
+			// por ejemplo una imagen de previsualización; reintentar no cambiaría nada
+			log.Printf("Moderación de %s sin publicación asociada", n.PublicID)
+		case err != nil:
+			log.Printf("Error aplicando moderación de %s: %v", n.PublicID, err)
+			httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
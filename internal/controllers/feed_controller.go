@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+const (
+	feedTitle       = "TalkUs"
+	feedDescription = "Publicaciones recientes en TalkUs"
+	feedSize        = 20
+	feedSummarySize = 280
+)
+
+// FeedController publica las publicaciones recientes como feeds RSS 2.0 y Atom.
+type FeedController struct {
+	postUsecase *usecases.PostUsecase
+	siteURL     string
+}
+
+// NewFeedController crea el controlador de feeds. siteURL es la URL base usada para los enlaces.
+func NewFeedController(u *usecases.PostUsecase, siteURL string) *FeedController {
+	return &FeedController{postUsecase: u, siteURL: siteURL}
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+// rssItem publica el ID del autor como dc:creator, ya que RSS exige un correo en <author>.
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator,omitempty"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Link    atomLink   `xml:"link"`
+	Updated string     `xml:"updated"`
+	Summary string     `xml:"summary"`
+	Author  atomAuthor `xml:"author"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// @Summary Feed RSS de publicaciones
+// @Description Retorna las publicaciones más recientes como un feed RSS 2.0. No incluye los reposts.
+// @Tags Feed
+// @Produce xml
+// @Success 200 {string} string "Feed RSS"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/feed.rss [get]
+func (c *FeedController) RSS(w http.ResponseWriter, r *http.Request) {
+	posts, ok := c.recentPosts(w, r)
+	if !ok {
+		return
+	}
+
+	channel := rssChannel{
+		Title:       feedTitle,
+		Link:        c.siteURL,
+		Description: feedDescription,
+		Items:       make([]rssItem, 0, len(posts)),
+	}
+	for _, p := range posts {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       p.Title,
+			Link:        c.postURL(p),
+			Description: usecases.Excerpt(p.Content, feedSummarySize),
+			Creator:     p.AuthorID,
+			GUID:        c.postURL(p),
+			PubDate:     p.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	writeXML(w, "application/rss+xml; charset=utf-8", rssFeed{Version: "2.0", Channel: channel})
+}
+
+// @Summary Feed Atom de publicaciones
+// @Description Retorna las publicaciones más recientes como un feed Atom. No incluye los reposts.
+// @Tags Feed
+// @Produce xml
+// @Success 200 {string} string "Feed Atom"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/feed.atom [get]
+func (c *FeedController) Atom(w http.ResponseWriter, r *http.Request) {
+	posts, ok := c.recentPosts(w, r)
+	if !ok {
+		return
+	}
+
+	updated := time.Now()
+	if len(posts) > 0 {
+		updated = posts[0].CreatedAt
+	}
+
+	feed := atomFeed{
+		Title:   feedTitle,
+		ID:      c.siteURL + "/",
+		Link:    atomLink{Href: c.siteURL, Rel: "alternate"},
+		Updated: updated.UTC().Format(time.RFC3339),
+		Entries: make([]atomEntry, 0, len(posts)),
+	}
+	for _, p := range posts {
+		author := p.AuthorID
+		if author == "" {
+			author = feedTitle
+		}
+		entryUpdated := p.UpdatedAt
+		if entryUpdated.IsZero() {
+			entryUpdated = p.CreatedAt
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   p.Title,
+			ID:      c.postURL(p),
+			Link:    atomLink{Href: c.postURL(p), Rel: "alternate"},
+			Updated: entryUpdated.UTC().Format(time.RFC3339),
+			Summary: usecases.Excerpt(p.Content, feedSummarySize),
+			Author:  atomAuthor{Name: author},
+		})
+	}
+
+	writeXML(w, "application/atom+xml; charset=utf-8", feed)
+}
+
+func (c *FeedController) recentPosts(w http.ResponseWriter, r *http.Request) ([]*models.Post, bool) {
+	posts, err := c.postUsecase.GetRecentPosts(r.Context(), feedSize, viewerID(r))
+	if err != nil {
+		log.Printf("Error obteniendo posts para el feed: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return nil, false
+	}
+	return posts, true
+}
+
+func (c *FeedController) postURL(p *models.Post) string {
+	return c.siteURL + "/posts/" + p.ID
+}
+
+// writeXML serializa payload en un buffer antes de responder, igual que httputil.WriteJSON.
+func writeXML(w http.ResponseWriter, contentType string, payload interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(payload); err != nil {
+		log.Printf("Error serializando feed: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error formateando datos")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+const testSiteURL = "https://talkus.test"
+
+// newTestFeedController sirve un feed con n publicaciones públicas, de la más reciente a la más
+// antigua, más una privada que no debe aparecer.
+func newTestFeedController(n int) *FeedController {
+	base := time.Date(2024, 5, 1, 13, 4, 5, 0, time.FixedZone("UTC-5", -5*60*60))
+	repo := &fakePostRepo{}
+	for i := range n {
+		created := base.Add(-time.Duration(i) * time.Hour)
+		repo.posts = append(repo.posts, &models.Post{
+			ID: fmt.Sprintf("post-%d", i+1), AuthorID: "u1", Title: fmt.Sprintf("Título <%d> & más", i+1),
+			Content:   "Contenido con **Markdown** y caracteres <especiales> & entidades",
+			CreatedAt: created, UpdatedAt: created,
+		})
+	}
+	repo.posts = append(repo.posts, &models.Post{ID: "privada", Title: "privada", Visibility: models.VisibilityPrivate})
+
+	posts := usecases.NewPostUsecase(repo, features.New(noFlags{}), usecases.PostUsecaseOptions{ReadingWPM: 200})
+	return NewFeedController(posts, testSiteURL)
+}
+
+// validRSS comprueba la estructura que exige RSS 2.0: canal con title, link y description, y
+// en cada ítem un título o descripción, un guid y un pubDate en formato RFC 822.
+type validRSS struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Items       []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			GUID        string `xml:"guid"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func TestRSSFeedIsValid(t *testing.T) {
+	c := newTestFeedController(feedSize + 5)
+	w := httptest.NewRecorder()
+	c.RSS(w, httptest.NewRequest(http.MethodGet, "/public/feed.rss", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/rss+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := w.Body.String()
+	if !strings.HasPrefix(body, xml.Header) {
+		t.Errorf("falta la declaración XML: %.60q", body)
+	}
+
+	var feed validRSS
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("el feed no es XML válido: %v", err)
+	}
+	if feed.Version != "2.0" {
+		t.Errorf("version = %q, se esperaba 2.0", feed.Version)
+	}
+	ch := feed.Channel
+	if ch.Title == "" || ch.Description == "" || !isAbsoluteURL(ch.Link) {
+		t.Errorf("canal incompleto: title=%q link=%q description=%q", ch.Title, ch.Link, ch.Description)
+	}
+	if len(ch.Items) != feedSize {
+		t.Fatalf("ítems = %d, se esperaban %d", len(ch.Items), feedSize)
+	}
+
+	var previous time.Time
+	for i, item := range ch.Items {
+		if item.Title == "" && item.Description == "" {
+			t.Errorf("ítem %d sin título ni descripción", i)
+		}
+		if !isAbsoluteURL(item.Link) || item.GUID != item.Link {
+			t.Errorf("ítem %d: link=%q guid=%q", i, item.Link, item.GUID)
+		}
+		if item.Creator == "" {
+			t.Errorf("ítem %d sin dc:creator", i)
+		}
+		pub, err := time.Parse(time.RFC1123Z, item.PubDate)
+		if err != nil {
+			t.Errorf("ítem %d: pubDate %q no es RFC 822: %v", i, item.PubDate, err)
+		}
+		if i > 0 && pub.After(previous) {
+			t.Errorf("ítem %d más reciente que el anterior", i)
+		}
+		previous = pub
+	}
+	if first := ch.Items[0]; first.Title != "Título <1> & más" || first.PubDate != "Wed, 01 May 2024 18:04:05 +0000" {
+		t.Errorf("primer ítem: title=%q pubDate=%q", first.Title, first.PubDate)
+	}
+	if strings.Contains(body, "privada") {
+		t.Error("el feed incluye una publicación privada")
+	}
+}
+
+// validAtom comprueba lo que exige RFC 4287: feed con id, title y updated; cada entrada con id,
+// title, updated y autor (el feed no declara uno propio), y fechas en RFC 3339.
+type validAtom struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Summary string `xml:"summary"`
+		Link    struct {
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Author struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+	} `xml:"entry"`
+}
+
+func TestAtomFeedIsValid(t *testing.T) {
+	c := newTestFeedController(3)
+	w := httptest.NewRecorder()
+	c.Atom(w, httptest.NewRequest(http.MethodGet, "/public/feed.atom", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	var feed validAtom
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("el feed no es XML Atom válido: %v", err)
+	}
+	if !isAbsoluteURL(feed.ID) || feed.Title == "" {
+		t.Errorf("feed incompleto: id=%q title=%q", feed.ID, feed.Title)
+	}
+	if feed.Updated != "2024-05-01T18:04:05Z" {
+		t.Errorf("updated = %q, se esperaba la fecha de la publicación más reciente", feed.Updated)
+	}
+	if len(feed.Links) == 0 || !isAbsoluteURL(feed.Links[0].Href) {
+		t.Errorf("feed sin enlace: %+v", feed.Links)
+	}
+	if len(feed.Entries) != 3 {
+		t.Fatalf("entradas = %d, se esperaban 3", len(feed.Entries))
+	}
+
+	ids := make(map[string]bool)
+	for i, e := range feed.Entries {
+		if !isAbsoluteURL(e.ID) || ids[e.ID] {
+			t.Errorf("entrada %d: id %q no es un IRI único", i, e.ID)
+		}
+		ids[e.ID] = true
+		if e.Title == "" || e.Summary == "" || e.Author.Name == "" || !isAbsoluteURL(e.Link.Href) {
+			t.Errorf("entrada %d incompleta: %+v", i, e)
+		}
+		if _, err := time.Parse(time.RFC3339, e.Updated); err != nil {
+			t.Errorf("entrada %d: updated %q no es RFC 3339: %v", i, e.Updated, err)
+		}
+	}
+}
+
+func TestFeedWithoutPostsIsValid(t *testing.T) {
+	c := newTestFeedController(0)
+	for name, handler := range map[string]http.HandlerFunc{"rss": c.RSS, "atom": c.Atom} {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/public/feed."+name, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", name, w.Code)
+		}
+		if err := xml.Unmarshal(w.Body.Bytes(), new(struct{})); err != nil {
+			t.Errorf("%s: el feed vacío no es XML válido: %v", name, err)
+		}
+	}
+}
+
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// Transformaciones de Cloudinary aplicadas al mostrar las imágenes de las publicaciones.
+const (
+	thumbnailTransformation = "c_thumb,w_300,h_300"
+	fullTransformation      = "c_limit,w_1600,q_auto"
+)
+
+// ImageController maneja las subidas de imágenes que no crean una publicación.
+type ImageController struct {
+	uploader  service.ImageUploader
+	janitor   *service.AssetJanitor
+	uploadCfg config.UploadConfig
+}
+
+// NewImageController crea un nuevo controlador de imágenes.
+func NewImageController(uploader service.ImageUploader, janitor *service.AssetJanitor, uploadCfg config.UploadConfig) *ImageController {
+	return &ImageController{uploader: uploader, janitor: janitor, uploadCfg: uploadCfg}
+}
+
+// ImagePreviewResponse contiene las URLs con las que se mostrará la imagen.
+type ImagePreviewResponse struct {
+	ThumbnailURL string           `json:"thumbnailUrl"`
+	FullURL      string           `json:"fullUrl"`
+	ExpiresAt    models.Timestamp `json:"expiresAt" swaggertype:"string" format:"date-time"`
+}
+
+// @Summary Previsualizar una imagen
+// @Description Sube la imagen de forma temporal y retorna las URLs de miniatura y de tamaño completo tal como se mostrarán, sin crear una publicación. La imagen temporal se elimina automáticamente al vencer. Requiere autenticación.
+// @Tags Image
+// @Accept multipart/form-data
+// @Produce json
+// @Param image formData file true "Imagen a previsualizar"
+// @Success 200 {object} ImagePreviewResponse "URLs de previsualización"
+// @Failure 400 {object} map[string]string "Imagen faltante o inválida"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 413 {object} map[string]string "El formulario supera el tamaño máximo permitido"
+// @Failure 500 {object} map[string]string "Error subiendo la imagen"
+// @Failure 504 {object} map[string]string "La subida de la imagen excedió UPLOAD_TIMEOUT"
+// @Router /public/images/preview [post]
+func (c *ImageController) Preview(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, c.uploadCfg.MaxRequestBytes)
+	if err := r.ParseMultipartForm(c.uploadCfg.MultipartMemoryBytes); err != nil {
+		http.Error(w, "Error parsing form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "image es obligatorio", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if !checkImage(w, file, header.Filename, c.uploadCfg) {
+		return
+	}
+
+	res, err := c.uploader.Upload(r.Context(), file, service.ImageUploadParams{
+		Folder:   service.PreviewFolder,
+		PublicID: service.NewPublicID("preview"),
+	})
+	if errors.Is(err, service.ErrUploadTimeout) {
+		http.Error(w, "La subida de la imagen tardó demasiado", http.StatusGatewayTimeout)
+		return
+	}
+	if err != nil {
+		log.Printf("Error subiendo previsualización: %v", err)
+		http.Error(w, "Error subiendo imagen", http.StatusInternalServerError)
+		return
+	}
+	c.janitor.ScheduleDestroy(res.PublicID, c.uploadCfg.PreviewTTL)
+
+	httputil.WriteJSON(w, http.StatusOK, ImagePreviewResponse{
+		ThumbnailURL: service.TransformedURL(res.SecureURL, thumbnailTransformation),
+		FullURL:      service.TransformedURL(res.SecureURL, fullTransformation),
+		ExpiresAt:    models.Timestamp(time.Now().Add(c.uploadCfg.PreviewTTL)),
+	})
+}
+
+// UploadConstraintsResponse describe los límites que el servidor aplica a las imágenes.
+type UploadConstraintsResponse struct {
+	MaxRequestBytes  int64    `json:"maxRequestBytes"`
+	AllowedMIMETypes []string `json:"allowedMimeTypes"`
+	// AllowedExtensions son las extensiones aceptadas en el nombre del archivo, que además
+	// debe corresponder a su contenido.
+	AllowedExtensions []string `json:"allowedExtensions"`
+	MaxImagesPerPost  int      `json:"maxImagesPerPost"`
+	MaxImageWidth     int      `json:"maxImageWidth"`
+	MaxImageHeight    int      `json:"maxImageHeight"`
+}
+
+// @Summary Consultar los límites de subida de imágenes
+// @Description Retorna los límites que el servidor aplica al subir imágenes, para que el cliente pueda validarlas antes de enviarlas. Los valores salen de la misma configuración con la que se validan las subidas.
+// @Tags Image
+// @Produce json
+// @Success 200 {object} UploadConstraintsResponse "Límites de subida"
+// @Router /public/config/upload [get]
+func (c *ImageController) Constraints(w http.ResponseWriter, r *http.Request) {
+	types := make([]string, 0, len(allowedImageTypes))
+	for _, mime := range allowedImageTypes {
+		types = append(types, mime)
+	}
+	sort.Strings(types)
+
+	httputil.WriteJSON(w, http.StatusOK, UploadConstraintsResponse{
+		MaxRequestBytes:   c.uploadCfg.MaxRequestBytes,
+		AllowedMIMETypes:  types,
+		AllowedExtensions: c.uploadCfg.AllowedExtensions,
+		MaxImagesPerPost:  maxImagesPerPost,
+		MaxImageWidth:     c.uploadCfg.MaxImageWidth,
+		MaxImageHeight:    c.uploadCfg.MaxImageHeight,
+	})
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+	_ "golang.org/x/image/webp"
+)
+
+// allowedImageTypes son los formatos aceptados, por el nombre que les da image.DecodeConfig.
+// Cada formato necesita su decodificador registrado en los imports de este archivo.
+var allowedImageTypes = map[string]string{
+	"gif":  "image/gif",
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"webp": "image/webp",
+}
+
+// maxImagesPerPost es el número de imágenes que acepta una publicación (el campo "image").
+const maxImagesPerPost = 1
+
+// checkImage lee solo la cabecera de la imagen para validar su formato, su extensión y sus
+// dimensiones antes de subirla, y deja el archivo listo para leerse desde el inicio. filename es
+// el nombre declarado por el cliente. Si la imagen no es válida responde al cliente y retorna false.
+func checkImage(w http.ResponseWriter, file io.ReadSeeker, filename string, cfg config.UploadConfig) bool {
+	if !checkImageHeader(w, file, filename, cfg) {
+		return false
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, "Error leyendo imagen: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// checkImageStream valida la imagen como checkImage cuando el archivo no se puede rebobinar,
+// por ejemplo al leerlo directo del cuerpo de la petición. Los bytes que consume la validación
+// se guardan con un TeeReader y el lector retornado los entrega antes que el resto del archivo.
+func checkImageStream(w http.ResponseWriter, file io.Reader, filename string, cfg config.UploadConfig) (io.Reader, bool) {
+	var head bytes.Buffer
+	if !checkImageHeader(w, io.TeeReader(file, &head), filename, cfg) {
+		return nil, false
+	}
+	return io.MultiReader(&head, file), true
+}
+
+// checkImageHeader valida la extensión de filename, decodifica la cabecera de la imagen y valida
+// su formato y dimensiones. La extensión debe estar en cfg.AllowedExtensions y corresponder al
+// formato detectado en el contenido, para rechazar archivos renombrados (un .exe como .png, o
+// un GIF como .jpg).
+func checkImageHeader(w http.ResponseWriter, file io.Reader, filename string, cfg config.UploadConfig) bool {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if !slices.Contains(cfg.AllowedExtensions, ext) {
+		http.Error(w, fmt.Sprintf("La extensión del archivo debe ser una de: %s",
+			strings.Join(cfg.AllowedExtensions, ", ")), http.StatusBadRequest)
+		return false
+	}
+
+	imgCfg, format, err := image.DecodeConfig(file)
+	mimeType, ok := allowedImageTypes[format]
+	if err != nil || !ok {
+		http.Error(w, "La imagen no tiene un formato válido", http.StatusBadRequest)
+		return false
+	}
+	if extType, _, _ := strings.Cut(mime.TypeByExtension("."+ext), ";"); extType != mimeType {
+		http.Error(w, fmt.Sprintf("La extensión .%s no coincide con el contenido del archivo (%s)", ext, mimeType),
+			http.StatusBadRequest)
+		return false
+	}
+	if imgCfg.Width > cfg.MaxImageWidth || imgCfg.Height > cfg.MaxImageHeight {
+		http.Error(w, fmt.Sprintf("La imagen excede las dimensiones máximas de %dx%d",
+			cfg.MaxImageWidth, cfg.MaxImageHeight), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
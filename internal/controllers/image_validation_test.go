@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+)
+
+func testJPEG(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, size, size)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testWebP retorna la cabecera de una imagen WebP sin pérdida (VP8L) de size x size, que alcanza
+// para que image.DecodeConfig la reconozca.
+func testWebP(size int) []byte {
+	vp8l := []byte{0x2f, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(vp8l[1:], uint32(size-1)|uint32(size-1)<<14)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+len(vp8l)))
+	buf.WriteString("WEBPVP8L")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(vp8l)))
+	buf.Write(vp8l)
+	buf.WriteByte(0) // relleno hasta un tamaño par
+	return buf.Bytes()
+}
+
+func TestCheckImageHeader(t *testing.T) {
+	cfg := config.UploadConfig{MaxImageWidth: 100, MaxImageHeight: 100, AllowedExtensions: []string{"png", "jpg", "jpeg", "webp"}}
+	pngImage, jpegImage, webpImage := testPNG(t, 2), testJPEG(t, 2), testWebP(2)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		filename string
+		wantOK   bool
+		wantBody string
+	}{
+		{"PNG como .png", pngImage, "foto.png", true, ""},
+		{"extensión en mayúsculas", pngImage, "FOTO.PNG", true, ""},
+		{"JPEG como .jpg", jpegImage, "foto.jpg", true, ""},
+		{"JPEG como .jpeg", jpegImage, "foto.jpeg", true, ""},
+		{"WebP como .webp", webpImage, "foto.webp", true, ""},
+		{"WebP como .png", webpImage, "foto.png", false, "no coincide con el contenido"},
+		{"WebP demasiado grande", testWebP(101), "foto.webp", false, "dimensiones máximas"},
+		{"PNG como .jpg", pngImage, "foto.jpg", false, "no coincide con el contenido"},
+		{"JPEG como .png", jpegImage, "foto.png", false, "no coincide con el contenido"},
+		{"texto como .png", []byte("no soy una imagen"), "foto.png", false, "formato válido"},
+		{"extensión no permitida", pngImage, "foto.exe", false, "La extensión del archivo debe ser una de"},
+		{"sin extensión", pngImage, "foto", false, "La extensión del archivo debe ser una de"},
+		{"demasiado grande", testPNG(t, 101), "foto.png", false, "dimensiones máximas"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ok := checkImageHeader(w, bytes.NewReader(tt.data), tt.filename, cfg)
+			if ok != tt.wantOK {
+				t.Fatalf("checkImageHeader() = %v, se esperaba %v (body = %s)", ok, tt.wantOK, w.Body)
+			}
+			if tt.wantOK {
+				if w.Body.Len() != 0 {
+					t.Errorf("no debería responder al aceptar la imagen: %s", w.Body)
+				}
+				return
+			}
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, se esperaba 400", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, se esperaba que contuviera %q", w.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCheckImageStreamReplaysHeader(t *testing.T) {
+	cfg := config.UploadConfig{MaxImageWidth: 100, MaxImageHeight: 100, AllowedExtensions: []string{"png"}}
+	data := testPNG(t, 2)
+
+	w := httptest.NewRecorder()
+	r, ok := checkImageStream(w, bytes.NewReader(data), "foto.png", cfg)
+	if !ok {
+		t.Fatalf("checkImageStream() rechazó una imagen válida: %s", w.Body)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("el lector retornado entrega %d bytes, se esperaban los %d del archivo", len(got), len(data))
+	}
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+	"github.com/JuanPidarraga/talkus-backend/internal/buildinfo"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+)
+
+// InfoController describe el servidor en ejecución para que los clientes adapten su
+// comportamiento.
+type InfoController struct {
+	flags          *features.Flags
+	uploadCfg      config.UploadConfig
+	dailyPostLimit int
+}
+
+// NewInfoController crea un nuevo controlador de información del servidor.
+func NewInfoController(flags *features.Flags, uploadCfg config.UploadConfig, dailyPostLimit int) *InfoController {
+	return &InfoController{flags: flags, uploadCfg: uploadCfg, dailyPostLimit: dailyPostLimit}
+}
+
+// InfoLimits contiene los límites que el servidor aplica a los clientes.
+type InfoLimits struct {
+	DailyPostLimit   int   `json:"dailyPostLimit"`
+	MaxPageSize      int   `json:"maxPageSize"`
+	MaxRequestBytes  int64 `json:"maxRequestBytes"`
+	MaxImagesPerPost int   `json:"maxImagesPerPost"`
+	MaxImageWidth    int   `json:"maxImageWidth"`
+	MaxImageHeight   int   `json:"maxImageHeight"`
+}
+
+// InfoResponse describe la versión, las funcionalidades activas y los límites del servidor.
+type InfoResponse struct {
+	Version  string          `json:"version"`
+	Commit   string          `json:"commit"`
+	Features map[string]bool `json:"features"`
+	Limits   InfoLimits      `json:"limits"`
+}
+
+// @Summary Consultar la información del servidor
+// @Description Retorna la versión y el commit con los que se compiló el servidor, el estado de cada funcionalidad configurable y los límites vigentes. dailyPostLimit es 0 si no hay límite diario.
+// @Tags Info
+// @Produce json
+// @Success 200 {object} InfoResponse "Información del servidor"
+// @Router /public/info [get]
+func (c *InfoController) Get(w http.ResponseWriter, r *http.Request) {
+	build := buildinfo.Get()
+	httputil.WriteJSON(w, http.StatusOK, InfoResponse{
+		Version:  build.Version,
+		Commit:   build.Commit,
+		Features: c.flags.All(),
+		Limits: InfoLimits{
+			DailyPostLimit:   c.dailyPostLimit,
+			MaxPageSize:      maxPageSize,
+			MaxRequestBytes:  c.uploadCfg.MaxRequestBytes,
+			MaxImagesPerPost: maxImagesPerPost,
+			MaxImageWidth:    c.uploadCfg.MaxImageWidth,
+			MaxImageHeight:   c.uploadCfg.MaxImageHeight,
+		},
+	})
+}
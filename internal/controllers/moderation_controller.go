@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// ModerationController maneja las rutas administrativas de moderación.
+type ModerationController struct {
+	usecase *usecases.ModerationUsecase
+}
+
+// NewModerationController crea un nuevo controlador de moderación.
+func NewModerationController(usecase *usecases.ModerationUsecase) *ModerationController {
+	return &ModerationController{usecase: usecase}
+}
+
+// @Summary Eliminar definitivamente una publicación
+// @Description Elimina la publicación y su imagen en Cloudinary sin importar su estado. Pensado para solicitudes legales o de retiro inmediato. Solo para administradores.
+// @Tags Admin
+// @Produce json
+// @Param id path string true "ID de la publicación"
+// @Param reason query string false "Motivo registrado en el log de auditoría"
+// @Success 204 "Publicación eliminada"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Permisos insuficientes"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/posts/{id} [delete]
+func (c *ModerationController) ForceDeletePost(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+
+	err := c.usecase.ForceDeletePost(r.Context(), token.UID, mux.Vars(r)["id"], r.URL.Query().Get("reason"))
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	}
+	if err != nil {
+		log.Printf("Error eliminando publicación: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TransferPostsRequest indica el usuario que recibe las publicaciones transferidas.
+type TransferPostsRequest struct {
+	UserID string `json:"userId" validate:"required"`
+}
+
+// TransferPostsResponse indica cuántas publicaciones se transfirieron.
+type TransferPostsResponse struct {
+	Transferred int `json:"transferred"`
+}
+
+// @Summary Transferir una publicación a otro usuario
+// @Description Reasigna el autor de la publicación al usuario indicado, que debe existir. Queda registrado en el log de auditoría. Solo para administradores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de la publicación"
+// @Param request body TransferPostsRequest true "Usuario de destino"
+// @Success 204 "Publicación transferida"
+// @Failure 400 {object} map[string]string "Solicitud inválida"
+// @Failure 404 {object} map[string]string "Publicación o usuario de destino no encontrado"
+// @Failure 422 {object} ValidationErrorResponse "Falta el usuario de destino"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/posts/{id}/transfer [post]
+func (c *ModerationController) TransferPost(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+
+	req, ok := decodeTransferRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := c.usecase.TransferPost(r.Context(), token.UID, mux.Vars(r)["id"], req.UserID)
+	switch {
+	case errors.Is(err, usecases.ErrPostNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	case errors.Is(err, usecases.ErrUserNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Usuario de destino no encontrado")
+		return
+	case err != nil:
+		log.Printf("Error transfiriendo publicación: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Transferir todas las publicaciones de un usuario
+// @Description Reasigna al usuario de destino todas las publicaciones del usuario indicado, por ejemplo al unificar cuentas duplicadas. Queda registrado en el log de auditoría. Solo para administradores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID del usuario de origen"
+// @Param request body TransferPostsRequest true "Usuario de destino"
+// @Success 200 {object} TransferPostsResponse "Número de publicaciones transferidas"
+// @Failure 400 {object} map[string]string "Solicitud inválida o mismo usuario de origen y destino"
+// @Failure 404 {object} map[string]string "Usuario de destino no encontrado"
+// @Failure 422 {object} ValidationErrorResponse "Falta el usuario de destino"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/users/{id}/posts/transfer [post]
+func (c *ModerationController) TransferAllPosts(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+
+	req, ok := decodeTransferRequest(w, r)
+	if !ok {
+		return
+	}
+
+	transferred, err := c.usecase.TransferAllPosts(r.Context(), token.UID, mux.Vars(r)["id"], req.UserID)
+	switch {
+	case errors.Is(err, usecases.ErrSameUser):
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, usecases.ErrUserNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Usuario de destino no encontrado")
+		return
+	case err != nil:
+		log.Printf("Error transfiriendo publicaciones (%d transferidas): %v", transferred, err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, TransferPostsResponse{Transferred: transferred})
+}
+
+func decodeTransferRequest(w http.ResponseWriter, r *http.Request) (TransferPostsRequest, bool) {
+	var req TransferPostsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Solicitud inválida")
+		return req, false
+	}
+	return req, validateRequest(w, req)
+}
+
+// MergePostsRequest indica la publicación que se conserva y los duplicados que se fusionan en ella.
+type MergePostsRequest struct {
+	PrimaryID    string   `json:"primaryId"    validate:"required"`
+	DuplicateIDs []string `json:"duplicateIds" validate:"required,min=1,max=10,dive,required"`
+	Reason       string   `json:"reason"`
+}
+
+// @Summary Fusionar publicaciones duplicadas
+// @Description Mueve a la publicación principal las reacciones y los reposts de los duplicados (máximo 10), le suma sus contadores de reacciones, reposts y vistas y marca los duplicados como fusionados, todo en una transacción. Los duplicados no se eliminan ni se borran sus imágenes: dejan de aparecer en las lecturas y sus slugs llevan a la principal. Si un usuario reaccionó a varias, conserva la reacción de la principal o la del primer duplicado. Cada duplicado se registra en el log de auditoría. Solo para administradores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body MergePostsRequest true "Publicación principal, duplicados y motivo"
+// @Success 200 {object} models.PostMerge "Resumen de la fusión"
+// @Failure 400 {object} map[string]string "Solicitud inválida o principal entre los duplicados"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Permisos insuficientes"
+// @Failure 404 {object} map[string]string "Alguna publicación no existe"
+// @Failure 413 {object} map[string]string "Demasiadas interacciones para fusionar de una vez"
+// @Failure 422 {object} ValidationErrorResponse "Falta la principal o la lista de duplicados está vacía o es demasiado larga"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/posts/merge [post]
+func (c *ModerationController) MergePosts(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+
+	var req MergePostsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Solicitud inválida")
+		return
+	}
+	if !validateRequest(w, req) {
+		return
+	}
+
+	merge, err := c.usecase.MergePosts(r.Context(), token.UID, req.PrimaryID, req.DuplicateIDs, req.Reason)
+	switch {
+	case errors.Is(err, usecases.ErrMergeIntoSelf):
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, usecases.ErrPostNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Alguna de las publicaciones no existe")
+		return
+	case errors.Is(err, usecases.ErrMergeTooLarge):
+		httputil.WriteError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error fusionando publicaciones: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, merge)
+}
+
+// ClearFlagsRequest contiene las publicaciones a las que se les quita la marca de reportada.
+type ClearFlagsRequest struct {
+	PostIDs []string `json:"postIds" validate:"required,min=1,max=100"`
+	Reason  string   `json:"reason"`
+}
+
+// ClearFlagsResponse contiene el resultado por publicación.
+type ClearFlagsResponse struct {
+	Results []usecases.FlagClearResult `json:"results"`
+}
+
+// @Summary Quitar la marca de reportada a varias publicaciones
+// @Description Quita en una sola operación la marca de reportada a las publicaciones indicadas (máximo 100) y retorna el resultado de cada una: cleared, not_found o error. Cada publicación actualizada se registra en el log de auditoría. Para administradores y moderadores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body ClearFlagsRequest true "Publicaciones y motivo"
+// @Success 200 {object} ClearFlagsResponse "Resultado por publicación"
+// @Failure 400 {object} map[string]string "Solicitud inválida"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Permisos insuficientes"
+// @Failure 422 {object} ValidationErrorResponse "Lista de publicaciones vacía o demasiado larga"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/posts/clear-flags [post]
+func (c *ModerationController) ClearFlags(w http.ResponseWriter, r *http.Request) {
+	token := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+
+	var req ClearFlagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Solicitud inválida")
+		return
+	}
+	if !validateRequest(w, req) {
+		return
+	}
+
+	results, err := c.usecase.ClearFlags(r.Context(), token.UID, req.PostIDs, req.Reason)
+	if err != nil {
+		log.Printf("Error quitando marcas: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ClearFlagsResponse{Results: results})
+}
+
+// @Summary Cola de revisión de publicaciones reportadas
+// @Description Lista las publicaciones reportadas que ningún moderador ha revisado, de la más antigua a la más reciente, para atenderlas en orden de llegada. Quitar la marca de reportada las saca de la cola. Para administradores y moderadores.
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Resultados por página (por defecto 50, máximo 200)"
+// @Param offset query int false "Número de resultados a omitir"
+// @Success 200 {array} models.Post "Publicaciones pendientes de revisión"
+// @Header 200 {string} Link "Enlaces a las páginas first, prev, next y last (RFC 8288)"
+// @Header 200 {int} X-Total-Count "Total de publicaciones pendientes de revisión"
+// @Failure 400 {object} map[string]string "Parámetros inválidos"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Permisos insuficientes"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/posts/review-queue [get]
+func (c *ModerationController) ReviewQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r, 50, maxPageSize)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	posts, total, err := c.usecase.ReviewQueue(r.Context(), limit, offset)
+	if err != nil {
+		log.Printf("Error obteniendo la cola de revisión: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	writePaginationHeaders(w, r, limit, offset, total)
+
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// NotificationController maneja las peticiones HTTP relacionadas a notificaciones.
+type NotificationController struct {
+	usecase *usecases.NotificationUsecase
+}
+
+// NewNotificationController crea un nuevo controlador de notificaciones.
+func NewNotificationController(usecase *usecases.NotificationUsecase) *NotificationController {
+	return &NotificationController{usecase: usecase}
+}
+
+// ownerOf retorna el usuario de la ruta si es el autenticado. Si no lo es responde 403 y
+// retorna false: cada usuario solo accede a sus propias notificaciones.
+func ownerOf(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := mux.Vars(r)["id"]
+	if userID != viewerID(r) {
+		httputil.WriteError(w, http.StatusForbidden, "Solo puedes acceder a tus propias notificaciones")
+		return "", false
+	}
+	return userID, true
+}
+
+// @Summary Listar las notificaciones de un usuario
+// @Description Retorna las notificaciones del usuario autenticado de la más reciente a la más antigua, junto con el número de no leídas. Solo el propio usuario puede consultarlas.
+// @Tags Notification
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Success 200 {object} models.NotificationList "Notificaciones del usuario"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "El usuario no es el autenticado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/notifications [get]
+func (c *NotificationController) GetByUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := ownerOf(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := c.usecase.GetUserNotifications(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error obteniendo notificaciones: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, list)
+}
+
+// UnreadCountResponse contiene el número de notificaciones no leídas de un usuario.
+type UnreadCountResponse struct {
+	UserID      string `json:"userId"`
+	UnreadCount int64  `json:"unreadCount"`
+}
+
+// @Summary Contar las notificaciones no leídas de un usuario
+// @Description Retorna solo el número de notificaciones no leídas, para el indicador de la campana. Se resuelve con una consulta de conteo, sin leer las notificaciones. Es 0 si el usuario no tiene notificaciones. Solo el propio usuario puede consultarlo.
+// @Tags Notification
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Success 200 {object} UnreadCountResponse "Notificaciones no leídas"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "El usuario no es el autenticado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/notifications/unread-count [get]
+func (c *NotificationController) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := ownerOf(w, r)
+	if !ok {
+		return
+	}
+
+	unread, err := c.usecase.CountUnread(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error contando notificaciones no leídas: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, UnreadCountResponse{UserID: userID, UnreadCount: unread})
+}
+
+// @Summary Marcar una notificación como leída
+// @Description Marca como leída la notificación indicada del usuario autenticado. Solo el propio usuario puede hacerlo.
+// @Tags Notification
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Param notificationId path string true "ID de la notificación"
+// @Success 200 {object} map[string]string "Notificación marcada como leída"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "El usuario no es el autenticado"
+// @Failure 404 {object} map[string]string "Notificación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/notifications/{notificationId}/read [post]
+func (c *NotificationController) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := ownerOf(w, r)
+	if !ok {
+		return
+	}
+
+	err := c.usecase.MarkRead(r.Context(), userID, mux.Vars(r)["notificationId"])
+	if errors.Is(err, usecases.ErrNotificationNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Notificación no encontrada")
+		return
+	}
+	if err != nil {
+		log.Printf("Error marcando notificación: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]string{"message": "Notificación marcada como leída"})
+}
+
+// MarkAllReadResponse contiene cuántas notificaciones se marcaron como leídas.
+type MarkAllReadResponse struct {
+	Updated int `json:"updated"`
+}
+
+// @Summary Marcar todas las notificaciones como leídas
+// @Description Marca como leídas todas las notificaciones no leídas del usuario autenticado y retorna cuántas se actualizaron. Solo el propio usuario puede hacerlo.
+// @Tags Notification
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Success 200 {object} MarkAllReadResponse "Notificaciones actualizadas"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "El usuario no es el autenticado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/notifications/read-all [post]
+func (c *NotificationController) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := ownerOf(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := c.usecase.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error marcando notificaciones: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, MarkAllReadResponse{Updated: updated})
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxPageSize es el mayor limit que aceptan los listados paginados.
+const maxPageSize = 200
+
+// parsePagination lee los parámetros ?limit= y ?offset= de la consulta. limit toma
+// defaultLimit si se omite y nunca supera maxLimit.
+func parsePagination(r *http.Request, defaultLimit, maxLimit int) (limit, offset int, err error) {
+	limit = defaultLimit
+	q := r.URL.Query()
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return 0, 0, errors.New("el parámetro 'limit' debe ser un entero positivo")
+		}
+		limit = min(limit, maxLimit)
+	}
+	if raw := q.Get("offset"); raw != "" {
+		offset, err = strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("el parámetro 'offset' debe ser un entero no negativo")
+		}
+	}
+	return limit, offset, nil
+}
+
+// writePaginationHeaders agrega X-Total-Count y una cabecera Link (RFC 8288) con las páginas
+// first, prev, next y last, conservando los demás parámetros de la consulta. prev y next se
+// omiten cuando no existen.
+func writePaginationHeaders(w http.ResponseWriter, r *http.Request, limit, offset int, total int64) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	pageURL := func(offset int) string {
+		q := r.URL.Query()
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(offset))
+		return r.URL.Path + "?" + q.Encode()
+	}
+
+	last := 0
+	if total > 0 {
+		last = int((total - 1) / int64(limit) * int64(limit))
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(0))}
+	if offset > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(max(offset-limit, 0))))
+	}
+	if int64(offset+limit) < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(offset+limit)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(last)))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
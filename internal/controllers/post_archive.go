@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/gorilla/mux"
+)
+
+// @Summary Archivo de publicaciones por mes
+// @Description Retorna cuántas publicaciones públicas se crearon en cada mes (UTC), del más reciente al más antiguo, para armar un archivo tipo blog. Los meses sin publicaciones se omiten. El conteo se recalcula cada 5 minutos.
+// @Tags Post
+// @Produce json
+// @Success 200 {array} models.ArchiveMonth "Publicaciones por mes"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/archive [get]
+func (c *PostController) Archive(w http.ResponseWriter, r *http.Request) {
+	months, err := c.postUsecase.GetArchive(r.Context())
+	if err != nil {
+		log.Printf("Error obteniendo el archivo de publicaciones: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, months)
+}
+
+// @Summary Publicaciones de un mes del archivo
+// @Description Lista las publicaciones públicas creadas en el mes indicado (UTC), de la más antigua a la más reciente, paginadas.
+// @Tags Post
+// @Produce json
+// @Param year path int true "Año"
+// @Param month path int true "Mes, de 1 a 12"
+// @Param limit query int false "Resultados por página (por defecto 50, máximo 200)"
+// @Param offset query int false "Número de resultados a omitir"
+// @Success 200 {array} models.Post "Publicaciones del mes"
+// @Header 200 {string} Link "Enlaces a las páginas first, prev, next y last (RFC 8288)"
+// @Header 200 {int} X-Total-Count "Total de publicaciones del mes"
+// @Failure 400 {object} map[string]string "Año, mes o paginación inválidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/archive/{year}/{month} [get]
+func (c *PostController) ArchiveMonth(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	year, err := strconv.Atoi(vars["year"])
+	if err != nil || year < 1 || year > 9999 {
+		httputil.WriteError(w, http.StatusBadRequest, "El año no es válido")
+		return
+	}
+	month, err := strconv.Atoi(vars["month"])
+	if err != nil || month < 1 || month > 12 {
+		httputil.WriteError(w, http.StatusBadRequest, "El mes debe estar entre 1 y 12")
+		return
+	}
+	limit, offset, err := parsePagination(r, 50, maxPageSize)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	posts, total, err := c.postUsecase.GetArchiveMonth(r.Context(), year, time.Month(month), limit, offset, viewerID(r))
+	if err != nil {
+		log.Printf("Error obteniendo publicaciones de %d-%02d: %v", year, month, err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	writePaginationHeaders(w, r, limit, offset, total)
+
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
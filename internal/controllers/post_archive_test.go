@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// archiveRepo sirve GetCreatedBetween desde las publicaciones guardadas.
+type archiveRepo struct{ fakePostRepo }
+
+func (f *archiveRepo) GetCreatedBetween(ctx context.Context, from, to time.Time) ([]*models.Post, error) {
+	return f.GetAll(ctx, models.PostFilter{})
+}
+
+func TestArchiveMonthReturnsBothImageURLs(t *testing.T) {
+	repo := &archiveRepo{}
+	repo.posts = []*models.Post{{
+		ID: "post-1", Title: "con imagen", CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		ImageURL:         "https://res.cloudinary.test/posts_images/a.webp",
+		ImageFallbackURL: "https://res.cloudinary.test/posts_images/a.jpg",
+	}}
+	flags := features.New(noFlags{})
+	posts := usecases.NewPostUsecase(repo, flags, usecases.PostUsecaseOptions{Views: usecases.NewViewCounter(nil, time.Minute), ReadingWPM: 200})
+	c := NewPostController(posts, nil, nil, nil, nil, config.UploadConfig{}, flags)
+
+	// la cabecera Accept de la API no dice qué formatos de imagen muestra el cliente
+	for _, accept := range []string{"", "application/json", "image/webp,*/*"} {
+		r := httptest.NewRequest(http.MethodGet, "/public/posts/archive/2024/5", nil)
+		r.Header.Set("Accept", accept)
+		r = mux.SetURLVars(r, map[string]string{"year": "2024", "month": "5"})
+		w := httptest.NewRecorder()
+		c.ArchiveMonth(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Accept %q: status = %d, body = %s", accept, w.Code, w.Body)
+		}
+		var got []struct {
+			ImageURL         string `json:"imageUrl"`
+			ImageFallbackURL string `json:"imageFallbackUrl"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].ImageURL != repo.posts[0].ImageURL || got[0].ImageFallbackURL != repo.posts[0].ImageFallbackURL {
+			t.Errorf("Accept %q: imágenes = %+v, se esperaban ambas URLs sin cambios", accept, got)
+		}
+	}
+}
@@ -1,128 +1,832 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/config"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
 	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
 	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
-	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/gorilla/mux"
 )
 
 type PostController struct {
 	postUsecase *usecases.PostUsecase
-	cld         *cloudinary.Cloudinary
+	users       *usecases.UserUsecase
+	blocks      *usecases.BlockUsecase
+	uploader    service.ImageUploader
+	retrier     *usecases.ImageRetrier
+	uploadCfg   config.UploadConfig
+	flags       *features.Flags
 }
 
-func NewPostController(u *usecases.PostUsecase, cld *cloudinary.Cloudinary) *PostController {
-	return &PostController{postUsecase: u, cld: cld}
+func NewPostController(u *usecases.PostUsecase, users *usecases.UserUsecase, blocks *usecases.BlockUsecase, uploader service.ImageUploader, retrier *usecases.ImageRetrier, uploadCfg config.UploadConfig, flags *features.Flags) *PostController {
+	return &PostController{postUsecase: u, users: users, blocks: blocks, uploader: uploader, retrier: retrier, uploadCfg: uploadCfg, flags: flags}
+}
+
+// parsePostSort lee el parámetro 'sort' de las listas de publicaciones. Si es inválido
+// responde 400 y retorna false.
+func parsePostSort(w http.ResponseWriter, r *http.Request) (usecases.PostSort, bool) {
+	order := usecases.PostSort(r.URL.Query().Get("sort"))
+	switch order {
+	case "":
+		return usecases.PostSortRecent, true
+	case usecases.PostSortRecent, usecases.PostSortViews, usecases.PostSortOldest:
+		return order, true
+	}
+	httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'sort' debe ser 'recent', 'views' u 'oldest'")
+	return "", false
+}
+
+// parseHasImage lee el filtro opcional 'hasImage' de las listas de publicaciones; retorna nil si
+// no se indicó. Si es inválido responde 400 y retorna false.
+func parseHasImage(w http.ResponseWriter, r *http.Request) (*bool, bool) {
+	raw := r.URL.Query().Get("hasImage")
+	if raw == "" {
+		return nil, true
+	}
+	hasImage, err := strconv.ParseBool(raw)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'hasImage' debe ser true o false")
+		return nil, false
+	}
+	return &hasImage, true
 }
 
 // @Summary Obtener todas las publicaciones
-// @Description Obtiene una lista de todas las publicaciones ordenadas por fecha de creación.
+// @Description Obtiene una lista de todas las publicaciones ordenadas por fecha de creación (de la más reciente o de la más antigua) o por número de vistas. Las publicaciones privadas o para seguidores solo se incluyen si quien consulta (autenticación opcional) es su autor. Para un usuario autenticado se aplican sus preferencias (ver /api/preferences): se omiten las etiquetas y autores silenciados y, si no se indica sort, se usa su orden por defecto. También se omiten los autores que bloqueó o que lo bloquearon. No incluye los reposts: los de cada usuario se listan en GET /public/users/{id}/reposts.
 // @Tags Post
 // @Accept json
 // @Produce json
+// @Param sort query string false "Orden de la lista: recent (por defecto, o el de las preferencias del usuario), views u oldest"
+// @Param include query string false "author incluye los datos mínimos del autor de cada publicación"
+// @Param hasImage query bool false "true deja solo las publicaciones con imagen; false, solo las que no tienen"
+// @Param If-Modified-Since header string false "Fecha del último Last-Modified recibido; si nada cambió desde entonces se responde 304 (no aplica con sort=views)"
 // @Success 200 {array} models.Post "Lista de publicaciones"
+// @Success 304 "Sin cambios desde If-Modified-Since"
+// @Failure 400 {object} map[string]string "Orden o filtro inválido"
 // @Failure 500 {object} map[string]string "Error interno del servidor"
 // @Router /public/posts [get]
 func (c *PostController) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	posts, err := c.postUsecase.GetAllPosts(ctx)
+	order, ok := parsePostSort(w, r)
+	if !ok {
+		return
+	}
+	hasImage, ok := parseHasImage(w, r)
+	if !ok {
+		return
+	}
+	filter := models.PostFilter{HasImage: hasImage}
+
+	prefs, ok := c.viewerPreferences(w, r)
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("sort") == "" && prefs.DefaultSort != "" {
+		order = usecases.PostSort(prefs.DefaultSort)
+	}
+	// los bloqueos los filtra PostUsecase; aquí solo importa si hay alguno, por Last-Modified
+	blocked, err := c.blocks.Hidden(r.Context(), viewerID(r))
+	if err != nil {
+		log.Printf("Error obteniendo bloqueos: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	filter.MutedTags = prefs.MutedTags
+	filter.MutedAuthorIDs = prefs.MutedUsers
+
+	// el orden por vistas cambia sin que cambie ninguna publicación, y Last-Modified no refleja
+	// los cambios de preferencias ni de bloqueos
+	if order != usecases.PostSortViews && prefs.IsZero() && len(blocked) == 0 && c.notModified(w, r) {
+		return
+	}
+
+	ctx := r.Context()
+	posts, err := c.postUsecase.GetAllPosts(ctx, filter, order, viewerID(r))
 	if err != nil {
 		log.Printf("Error obteniendo posts: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{
-			"error": "Error interno del servidor",
-		})
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	if !c.embedIncludes(w, r, posts) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(posts)
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
+
+// viewerPreferences retorna las preferencias de listado de quien consulta, vacías si no está
+// autenticado. Si no se pueden leer responde 500 y retorna false.
+func (c *PostController) viewerPreferences(w http.ResponseWriter, r *http.Request) (*models.UserPreferences, bool) {
+	viewer := viewerID(r)
+	if viewer == "" {
+		return &models.UserPreferences{}, true
+	}
+	prefs, err := c.users.GetPreferences(r.Context(), viewer)
+	if errors.Is(err, usecases.ErrUserNotFound) {
+		// usuario de Firebase sin documento: no tiene preferencias guardadas
+		return &models.UserPreferences{}, true
+	}
+	if err != nil {
+		log.Printf("Error obteniendo preferencias: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return nil, false
+	}
+	return prefs, true
+}
+
+// notModified agrega la cabecera Last-Modified con la fecha del último cambio en las
+// publicaciones y, si no hubo cambios desde If-Modified-Since, responde 304 y retorna true.
+// Si la fecha no se puede obtener la respuesta sigue sin la cabecera.
+func (c *PostController) notModified(w http.ResponseWriter, r *http.Request) bool {
+	modified, err := c.postUsecase.LastModified(r.Context())
+	if err != nil {
+		log.Printf("Error obteniendo fecha de último cambio: %v", err)
+		return false
+	}
+	if modified.IsZero() {
+		return false
+	}
+
+	w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err == nil && !modified.Truncate(time.Second).After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// @Summary Obtener una publicación
+// @Description Retorna la publicación y cuenta una vista. Las publicaciones que quien consulta no puede ver, o cuyo autor tiene un bloqueo con quien consulta, responden 404. Las lecturas repetidas de un mismo visitante (cabecera X-Session-ID o, en su defecto, su IP) se cuentan una sola vez dentro de la ventana configurada.
+// @Tags Post
+// @Produce json
+// @Param id path string true "ID de la publicación"
+// @Param include query string false "author incluye los datos mínimos del autor"
+// @Param X-Session-ID header string false "Identificador de la sesión del visitante"
+// @Success 200 {object} models.Post "Publicación"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/{id} [get]
+func (c *PostController) GetByID(w http.ResponseWriter, r *http.Request) {
+	post, err := c.postUsecase.GetPost(r.Context(), mux.Vars(r)["id"], viewerKey(r), viewerID(r))
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo post: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	if !c.embedIncludes(w, r, []*models.Post{post}) {
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, post)
+}
+
+// @Summary Obtener una publicación al azar
+// @Description Retorna una publicación pública y no reportada elegida al azar, para el botón "sorpréndeme". No cuenta una vista.
+// @Tags Post
+// @Produce json
+// @Success 200 {object} models.Post "Publicación"
+// @Failure 404 {object} map[string]string "No hay publicaciones"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/random [get]
+func (c *PostController) Random(w http.ResponseWriter, r *http.Request) {
+	post, err := c.postUsecase.GetRandomPost(r.Context(), viewerID(r))
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "No hay publicaciones")
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo post al azar: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, post)
+}
+
+// embedIncludes completa lo pedido en el parámetro 'include' (por ahora solo "author"). Si
+// falla responde 500 y retorna false.
+func (c *PostController) embedIncludes(w http.ResponseWriter, r *http.Request, posts []*models.Post) bool {
+	for _, inc := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if strings.TrimSpace(inc) != "author" {
+			continue
+		}
+		if err := c.users.EmbedAuthors(r.Context(), posts); err != nil {
+			log.Printf("Error obteniendo autores: %v", err)
+			httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+			return false
+		}
+	}
+	return true
+}
+
+// @Summary Resolver una referencia a una publicación
+// @Description Retorna la publicación a la que apunta ref, para que los enlaces funcionen sin importar su forma. Por ahora las publicaciones solo se identifican por su ID. Cuenta una vista igual que GET /public/posts/{id}.
+// @Tags Post
+// @Produce json
+// @Param ref query string true "Referencia a la publicación"
+// @Success 200 {object} models.Post "Publicación"
+// @Failure 400 {object} map[string]string "Falta el parámetro 'ref'"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/resolve [get]
+func (c *PostController) Resolve(w http.ResponseWriter, r *http.Request) {
+	ref := strings.TrimSpace(r.URL.Query().Get("ref"))
+	if ref == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "Falta el parámetro 'ref'")
+		return
+	}
+
+	post, err := c.postUsecase.ResolvePost(r.Context(), ref, viewerKey(r), viewerID(r))
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	}
+	if err != nil {
+		log.Printf("Error resolviendo referencia %q: %v", ref, err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, post)
+}
+
+// viewerKey identifica al visitante para el conteo de vistas: la sesión enviada por el
+// cliente o, si no la envía, su dirección IP.
+func viewerKey(r *http.Request) string {
+	if session := r.Header.Get("X-Session-ID"); session != "" {
+		return "session:" + session
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// CreatePostRequest contiene los campos de una nueva publicación, compartidos por la
+// creación vía JSON y vía multipart/form-data.
+type CreatePostRequest struct {
+	// Title y Content son obligatorios u opcionales según Type; ver usecases.ValidatePostType.
+	Title   string `json:"title"   validate:"max=200"`
+	Content string `json:"content" validate:"max=10000"`
+	// Type es standard (por defecto), question o announcement.
+	Type string `json:"type,omitempty" validate:"omitempty,oneof=standard question announcement"`
+	// Slug es opcional; si falta o, según la configuración, ya está en uso, se genera desde el título.
+	Slug string `json:"slug,omitempty" validate:"omitempty,max=80"`
+	// ExpiresAt es opcional; si se envía debe ser una fecha futura.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" validate:"omitempty,gt"`
+	// Visibility es public (por defecto), followers o private; las dos últimas requieren autenticación.
+	Visibility string `json:"visibility,omitempty" validate:"omitempty,oneof=public followers private"`
+	// CreatedAt solo se respeta si quien crea la publicación es administrador, por ejemplo al
+	// importar historial; para los demás siempre se usa la hora del servidor.
+	CreatedAt *time.Time `json:"createdAt,omitempty" validate:"omitempty,lte"`
 }
 
 // @Summary Crear una nueva publicación
-// @Description Permite crear una nueva publicación con un título, contenido y una imagen opcional. La imagen se sube a Cloudinary y se guarda la URL en la publicación.
+// @Description Permite crear una nueva publicación con un título, contenido y una imagen opcional. Acepta multipart/form-data (con imagen, que se sube a Cloudinary y se guarda su URL) o application/json (sin imagen). Con UPLOAD_STREAM=true la imagen se envía a Cloudinary a medida que llega, por lo que debe ser el último campo del formulario.
 // @Tags Post
 // @Accept multipart/form-data
+// @Accept json
 // @Produce json
-// @Param title formData string true "Título de la publicación"
-// @Param content formData string true "Contenido de la publicación"
+// @Param type formData string false "Tipo: standard (por defecto, requiere título y contenido), question (requiere contenido) o announcement (requiere título)"
+// @Param title formData string false "Título de la publicación"
+// @Param content formData string false "Contenido de la publicación"
 // @Param image formData file false "Imagen para la publicación"
-// @Success 201 {object} models.Post "Publicación creada exitosamente"
-// @Failure 400 {object} map[string]string "Solicitud inválida, título o contenido faltante"
+// @Param expiresAt formData string false "Fecha futura (RFC 3339) en que la publicación se elimina automáticamente"
+// @Param slug formData string false "Slug para la URL; se normaliza (minúsculas, sin tildes, espacios como guiones). Si falta se genera desde el título"
+// @Param visibility formData string false "Visibilidad: public (por defecto), followers o private. Las dos últimas requieren autenticación"
+// @Param createdAt formData string false "Fecha de creación (RFC 3339, no futura). Solo se respeta para administradores"
+// @Success 201 {object} models.Post "Publicación creada exitosamente; con imagePending en true si la imagen se subirá más tarde"
+// @Failure 400 {object} map[string]string "Solicitud inválida, imagen que excede las dimensiones permitidas o enlace a un dominio bloqueado (con POST_BLOCKED_DOMAINS_MODE=reject)"
+// @Failure 401 {object} map[string]string "Token de autorización inválido, o falta para una publicación no pública"
+// @Failure 413 {object} map[string]string "El formulario supera el tamaño máximo permitido"
+// @Failure 429 {object} map[string]string "El usuario alcanzó POST_DAILY_LIMIT publicaciones en 24 horas"
+// @Failure 422 {object} ValidationErrorResponse "Campos inválidos, por ejemplo título o contenido faltante según el tipo"
+// @Failure 409 {object} map[string]string "El slug ya está en uso (con POST_SLUG_CONFLICT_REJECT=true) o no se pudo asignar un identificador único a la imagen"
 // @Failure 500 {object} map[string]string "Error interno al crear la publicación"
+// @Failure 503 {object} map[string]string "Cloudinary no responde y la cola de imágenes pendientes está llena"
+// @Failure 504 {object} map[string]string "La subida de la imagen excedió UPLOAD_TIMEOUT"
 // @Router /public/posts [post]
 func (c *PostController) Create(w http.ResponseWriter, r *http.Request) {
 	//comprobar Content-Type y parsear form
 	ct := r.Header.Get("Content-Type")
+	if strings.HasPrefix(ct, "application/json") {
+		c.createFromJSON(w, r)
+		return
+	}
 	if !strings.HasPrefix(ct, "multipart/form-data") {
-		http.Error(w, "Content-Type debe ser multipart/form-data", http.StatusBadRequest)
+		http.Error(w, "Content-Type debe ser multipart/form-data o application/json", http.StatusBadRequest)
 		return
 	}
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		http.Error(w, "Error parsing form: "+err.Error(), http.StatusBadRequest)
+	r.Body = http.MaxBytesReader(w, r.Body, c.uploadCfg.MaxRequestBytes)
+	if c.uploadCfg.StreamUploads {
+		c.createStreaming(w, r)
+		return
+	}
+	if err := r.ParseMultipartForm(c.uploadCfg.MultipartMemoryBytes); err != nil {
+		c.writeFormError(w, err)
 		return
 	}
 
 	//leer directamente los valores del form
-	title := r.FormValue("title")
-	content := r.FormValue("content")
-	if title == "" || content == "" {
-		http.Error(w, "title y content son obligatorios", http.StatusBadRequest)
+	req, ok := formPostRequest(w, r, r.FormValue)
+	if !ok {
 		return
 	}
 
 	//subir imagen
-	var imageURL string
-	file, _, err := r.FormFile("image")
+	var img uploadedImage
+	file, header, err := r.FormFile("image")
 	if err == nil {
 		defer file.Close()
-		uploadParams := uploader.UploadParams{
-			Folder:    "posts_images",
-			PublicID:  fmt.Sprintf("post_%d", time.Now().Unix()),
-			Overwrite: func(b bool) *bool { return &b }(true),
+
+		if !checkImage(w, file, header.Filename, c.uploadCfg) {
+			return
 		}
-		res, err := c.cld.Upload.Upload(r.Context(), file, uploadParams)
-		if err != nil {
-			http.Error(w, "Error subiendo imagen: "+err.Error(), http.StatusInternalServerError)
+
+		uploadParams := c.postUploadParams()
+		res, err := c.uploadPostImage(r, file, &uploadParams)
+		if c.shouldDefer(err) {
+			c.saveWithPendingImage(w, r, req, file, uploadParams)
+			return
+		}
+		if !writeUploadError(w, err) {
 			return
 		}
-		imageURL = res.SecureURL
+		img = uploadedImageFrom(res)
+	}
+
+	c.savePost(w, r, req, img)
+}
+
+// writeFormError responde al error de leer un formulario multipart: 413 si supera
+// MaxRequestBytes y 400 en otro caso.
+func (c *PostController) writeFormError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("La solicitud supera el máximo de %d MB", c.uploadCfg.MaxRequestBytes>>20),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "Error parsing form: "+err.Error(), http.StatusBadRequest)
+}
+
+// formPostRequest arma y valida la solicitud a partir de los campos del formulario que
+// retorna get. Si no es válida responde al cliente y retorna false.
+func formPostRequest(w http.ResponseWriter, r *http.Request, get func(string) string) (CreatePostRequest, bool) {
+	req := CreatePostRequest{
+		Title:      get("title"),
+		Content:    get("content"),
+		Visibility: get("visibility"),
+		Slug:       get("slug"),
+		Type:       get("type"),
+	}
+	if raw := get("expiresAt"); raw != "" {
+		expiresAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "expiresAt debe tener formato RFC 3339", http.StatusBadRequest)
+			return req, false
+		}
+		req.ExpiresAt = &expiresAt
 	}
+	if raw := get("createdAt"); raw != "" && isAdmin(r) {
+		createdAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "createdAt debe tener formato RFC 3339", http.StatusBadRequest)
+			return req, false
+		}
+		req.CreatedAt = &createdAt
+	}
+	if !validateRequest(w, req) || !checkPostType(w, req) || !checkAuthor(w, r, req) {
+		return req, false
+	}
+	return req, true
+}
+
+// postUploadParams retorna los parámetros de subida de una imagen nueva de publicación.
+func (c *PostController) postUploadParams() service.ImageUploadParams {
+	params := service.ImageUploadParams{
+		Folder:   "posts_images",
+		PublicID: service.NewPublicID("post"),
+	}
+	if c.uploadCfg.ConvertToWebP {
+		params.Format = "webp"
+	}
+	return params
+}
+
+// writeUploadError responde al error de subir la imagen de una publicación. Retorna true si
+// err es nil y la publicación puede guardarse.
+func writeUploadError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, service.ErrUploadTimeout):
+		http.Error(w, "La subida de la imagen tardó demasiado", http.StatusGatewayTimeout)
+	case errors.Is(err, service.ErrPublicIDTaken):
+		log.Printf("Colisión de PublicID al subir imagen: %v", err)
+		http.Error(w, "No se pudo guardar la imagen, intenta de nuevo", http.StatusConflict)
+	default:
+		http.Error(w, "Error subiendo imagen: "+err.Error(), http.StatusInternalServerError)
+	}
+	return false
+}
+
+// uploadedImageFrom retorna los datos a guardar de una imagen recién subida.
+func uploadedImageFrom(res *service.ImageUploadResult) uploadedImage {
+	img := uploadedImage{URL: res.SecureURL, PublicID: res.PublicID}
+	if res.Format == "webp" {
+		img.FallbackURL = service.URLWithFormat(res.SecureURL, "jpg")
+	}
+	return img
+}
 
+// uploadPostImage sube la imagen de una publicación sin reemplazar imágenes existentes. Si el
+// PublicID ya está en uso lo reintenta una vez con otro, actualizando params.
+func (c *PostController) uploadPostImage(r *http.Request, file multipart.File, params *service.ImageUploadParams) (*service.ImageUploadResult, error) {
+	res, err := c.uploader.Upload(r.Context(), file, *params)
+	if !errors.Is(err, service.ErrPublicIDTaken) {
+		return res, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	params.PublicID = service.NewPublicID("post")
+	return c.uploader.Upload(r.Context(), file, *params)
+}
+
+// createFromJSON crea una publicación sin imagen a partir de un cuerpo JSON estricto.
+func (c *PostController) createFromJSON(w http.ResponseWriter, r *http.Request) {
+	var req CreatePostRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+	if !isAdmin(r) {
+		req.CreatedAt = nil
+	}
+	if !validateRequest(w, req) || !checkPostType(w, req) || !checkAuthor(w, r, req) {
+		return
+	}
+
+	c.savePost(w, r, req, uploadedImage{})
+}
+
+// shouldDefer indica si la subida falló porque Cloudinary no está disponible y la
+// configuración permite crear la publicación sin esperar la imagen.
+func (c *PostController) shouldDefer(err error) bool {
+	if !c.uploadCfg.DeferOnFailure || err == nil {
+		return false
+	}
+	return errors.Is(err, service.ErrUploadTimeout) || errors.Is(err, service.ErrUploaderUnavailable)
+}
+
+// saveWithPendingImage crea la publicación sin imagen y deja la imagen en la cola de
+// reintentos. Si la cola está llena responde 503 sin crear la publicación.
+func (c *PostController) saveWithPendingImage(w http.ResponseWriter, r *http.Request, req CreatePostRequest, file multipart.File, params service.ImageUploadParams) {
+	if !c.retrier.Reserve() {
+		http.Error(w, "El servicio de imágenes no está disponible, intenta más tarde", http.StatusServiceUnavailable)
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, "Error leyendo imagen", http.StatusInternalServerError)
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Error leyendo imagen", http.StatusInternalServerError)
+		return
+	}
+
+	created, ok := c.createPost(w, r, req, uploadedImage{Pending: true})
+	if !ok {
+		return
+	}
+	if err := c.retrier.Enqueue(created.ID, data, params); err != nil {
+		log.Printf("No se pudo encolar la imagen de %s: %v", created.ID, err)
+	}
+	httputil.WriteJSON(w, http.StatusCreated, created)
+}
+
+// uploadedImage agrupa los datos de la imagen ya subida que se guardan en la publicación.
+type uploadedImage struct {
+	URL         string
+	FallbackURL string
+	PublicID    string
+	// Pending indica que la imagen se subirá más tarde.
+	Pending bool
+}
+
+// savePost construye el modelo a partir de la solicitud ya validada y lo guarda. Si no se
+// puede guardar, elimina la imagen ya subida para no dejarla huérfana.
+func (c *PostController) savePost(w http.ResponseWriter, r *http.Request, req CreatePostRequest, img uploadedImage) {
+	created, ok := c.createPost(w, r, req, img)
+	if !ok {
+		service.DiscardUpload(r.Context(), c.uploader, img.PublicID)
+		return
+	}
+
+	// devolver JSON
+	httputil.WriteJSON(w, http.StatusCreated, created)
+}
+
+// createPost arma y guarda la publicación. Si falla escribe el error y retorna false.
+func (c *PostController) createPost(w http.ResponseWriter, r *http.Request, req CreatePostRequest, img uploadedImage) (*models.Post, bool) {
 	//crear el modelo
 	now := time.Now()
+	createdAt := now
+	if req.CreatedAt != nil {
+		createdAt = *req.CreatedAt
+	}
 	post := &models.Post{
-		Title:     title,
-		Content:   content,
-		ImageURL:  imageURL,
-		Likes:     0,
-		Dislikes:  0,
-		IsFlagged: false,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-
-	// 5) guardar
-	created, err := c.postUsecase.CreatePost(r.Context(), post)
+		AuthorID:         viewerID(r),
+		Visibility:       req.Visibility,
+		Slug:             req.Slug,
+		Type:             req.Type,
+		Title:            req.Title,
+		Content:          req.Content,
+		ImageURL:         img.URL,
+		ImageFallbackURL: img.FallbackURL,
+		ImagePublicID:    img.PublicID,
+		ImagePending:     img.Pending,
+		ExpiresAt:        req.ExpiresAt,
+		Likes:            0,
+		Dislikes:         0,
+		IsFlagged:        false,
+		CreatedAt:        createdAt,
+		UpdatedAt:        now,
+	}
+
+	// guardar
+	created, err := c.postUsecase.CreatePost(r.Context(), post, isStaff(r))
+	var typeErr *usecases.PostValidationError
+	if errors.As(err, &typeErr) {
+		writePostValidationError(w, typeErr)
+		return nil, false
+	}
+	if errors.Is(err, usecases.ErrInvalidSlug) || errors.Is(err, usecases.ErrSlugReserved) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return nil, false
+	}
+	if errors.Is(err, usecases.ErrBlockedDomain) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if errors.Is(err, usecases.ErrSlugTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return nil, false
+	}
+	if errors.Is(err, usecases.ErrDailyPostLimit) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return nil, false
+	}
 	if err != nil {
 		log.Printf("Error creando post: %v", err)
 		http.Error(w, "No se pudo crear el post", http.StatusInternalServerError)
+		return nil, false
+	}
+	return created, true
+}
+
+const (
+	defaultRelatedLimit = 5
+	maxRelatedLimit     = 20
+
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 10
+	maxSuggestQuery     = 100
+)
+
+// @Summary Sugerir publicaciones por título
+// @Description Retorna hasta limit publicaciones públicas cuyo título empieza con q, sin distinguir mayúsculas, en orden alfabético. Pensado para autocompletar un buscador: solo incluye ID y título, y la respuesta puede guardarse en caché por 30 segundos.
+// @Tags Post
+// @Produce json
+// @Param q query string true "Prefijo del título"
+// @Param limit query int false "Número máximo de resultados (por defecto 5, máximo 10)"
+// @Success 200 {array} usecases.PostSuggestion "Sugerencias"
+// @Failure 400 {object} map[string]string "Parámetros inválidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/suggest [get]
+func (c *PostController) Suggest(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" || len([]rune(q)) > maxSuggestQuery {
+		httputil.WriteError(w, http.StatusBadRequest,
+			fmt.Sprintf("El parámetro 'q' es obligatorio y admite hasta %d caracteres", maxSuggestQuery))
+		return
+	}
+
+	limit := defaultSuggestLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'limit' debe ser un entero positivo")
+			return
+		}
+		limit = min(n, maxSuggestLimit)
+	}
+
+	suggestions, err := c.postUsecase.SuggestPosts(r.Context(), q, limit, viewerID(r))
+	if err != nil {
+		log.Printf("Error sugiriendo publicaciones: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	httputil.WriteJSON(w, http.StatusOK, suggestions)
+}
+
+// SlugAvailability es la respuesta de SlugAvailable.
+type SlugAvailability struct {
+	// Slug es el slug normalizado, tal como se guardaría.
+	Slug      string `json:"slug"`
+	Available bool   `json:"available"`
+}
+
+// @Summary Comprobar si un slug está libre
+// @Description Normaliza el slug como al crear una publicación (minúsculas, sin tildes, espacios como guiones) e indica si ninguna publicación lo usa. Los slugs reservados para rutas del sitio se reportan como no disponibles.
+// @Tags Post
+// @Produce json
+// @Param slug query string true "Slug a comprobar"
+// @Success 200 {object} SlugAvailability "Disponibilidad del slug normalizado"
+// @Failure 400 {object} map[string]string "Slug vacío o con caracteres no permitidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/slug-available [get]
+func (c *PostController) SlugAvailable(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("slug")
+	if strings.TrimSpace(raw) == "" {
+		httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'slug' es obligatorio")
+		return
+	}
+
+	slug, available, err := c.postUsecase.SlugAvailable(r.Context(), raw)
+	if errors.Is(err, usecases.ErrInvalidSlug) {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error comprobando el slug %q: %v", slug, err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, SlugAvailability{Slug: slug, Available: available})
+}
+
+// @Summary Obtener publicaciones relacionadas
+// @Description Retorna publicaciones que comparten etiquetas con la indicada, ordenadas por etiquetas en común y fecha. No incluye la publicación original.
+// @Tags Post
+// @Produce json
+// @Param id path string true "ID de la publicación"
+// @Param limit query int false "Número máximo de resultados (por defecto 5, máximo 20)"
+// @Success 200 {array} models.Post "Publicaciones relacionadas"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/{id}/related [get]
+func (c *PostController) GetRelated(w http.ResponseWriter, r *http.Request) {
+	if !c.flags.IsEnabled(features.RelatedPosts) {
+		httputil.WriteError(w, http.StatusNotFound, "Funcionalidad no disponible")
+		return
+	}
+
+	limit := defaultRelatedLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'limit' debe ser un entero positivo")
+			return
+		}
+		limit = min(n, maxRelatedLimit)
+	}
+
+	posts, err := c.postUsecase.GetRelatedPosts(r.Context(), mux.Vars(r)["id"], limit, viewerID(r))
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo publicaciones relacionadas: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
+
+// @Summary Actualizar las etiquetas de una publicación
+// @Description Reemplaza las etiquetas de la publicación por la lista enviada, normalizada (sin espacios, en minúsculas, sin duplicados y en orden alfabético). No modifica el contenido. Solo para el autor de la publicación, moderadores y administradores.
+// @Tags Post
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de la publicación"
+// @Param tags body []string true "Nuevas etiquetas"
+// @Success 200 {array} string "Etiquetas guardadas"
+// @Failure 400 {object} map[string]string "Lista de etiquetas inválida"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Quien edita no es el autor ni un moderador"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/{id}/tags [put]
+func (c *PostController) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	var tags []string
+	if err := json.NewDecoder(r.Body).Decode(&tags); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Se esperaba un arreglo JSON de etiquetas")
+		return
+	}
+
+	updated, err := c.postUsecase.UpdateTags(r.Context(), mux.Vars(r)["id"], tags, viewerID(r), isStaff(r))
+	switch {
+	case errors.Is(err, usecases.ErrInvalidTags):
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, usecases.ErrNotPostAuthor):
+		httputil.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, usecases.ErrPostNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	case err != nil:
+		log.Printf("Error actualizando etiquetas: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
 		return
 	}
 
-	// 6) devolver JSON
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(created)
+	httputil.WriteJSON(w, http.StatusOK, updated)
+}
+
+// checkAuthor exige autenticación para las publicaciones no públicas, que necesitan un autor
+// para saber quién puede verlas. Si falta responde 401 y retorna false.
+func checkAuthor(w http.ResponseWriter, r *http.Request, req CreatePostRequest) bool {
+	if req.Visibility == "" || req.Visibility == models.VisibilityPublic || viewerID(r) != "" {
+		return true
+	}
+	httputil.WriteError(w, http.StatusUnauthorized, "Se requiere autenticación para crear publicaciones no públicas")
+	return false
+}
+
+// checkPostType aplica las reglas del tipo de publicación antes de subir la imagen, para no
+// dejarla huérfana si la publicación no puede crearse. Si no se cumplen responde 422.
+func checkPostType(w http.ResponseWriter, req CreatePostRequest) bool {
+	err := usecases.ValidatePostType(&models.Post{
+		Type:    req.Type,
+		Title:   usecases.SanitizeText(req.Title),
+		Content: usecases.SanitizeText(req.Content),
+	})
+	var typeErr *usecases.PostValidationError
+	if errors.As(err, &typeErr) {
+		writePostValidationError(w, typeErr)
+		return false
+	}
+	return true
+}
+
+// writePostValidationError responde 422 con los campos que no cumplen las reglas del tipo.
+func writePostValidationError(w http.ResponseWriter, err *usecases.PostValidationError) {
+	httputil.WriteJSON(w, http.StatusUnprocessableEntity, ValidationErrorResponse{
+		Error:  "La publicación no cumple las reglas del tipo " + err.Type,
+		Fields: err.Fields,
+	})
+}
+
+// viewerID retorna el UID del usuario autenticado, o "" si la petición es anónima.
+func viewerID(r *http.Request) string {
+	token, _ := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+	if token == nil {
+		return ""
+	}
+	return token.UID
+}
+
+// isStaff indica si la petición viene de un moderador o administrador autenticado.
+func isStaff(r *http.Request) bool {
+	token, _ := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+	return middleware.HasRole(token, middleware.RoleAdmin, middleware.RoleModerator)
+}
+
+// isAdmin indica si la petición viene de un administrador autenticado.
+func isAdmin(r *http.Request) bool {
+	token, _ := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+	return middleware.HasRole(token, middleware.RoleAdmin)
 }
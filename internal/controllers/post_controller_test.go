@@ -0,0 +1,465 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/config"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+// fakePostRepo guarda las publicaciones en memoria. Los métodos que no implementa entran en
+// panic a través de la interfaz embebida nil.
+type fakePostRepo struct {
+	usecases.PostRepository
+
+	mu      sync.Mutex
+	posts   []*models.Post
+	creates int
+	err     error
+}
+
+func (f *fakePostRepo) Create(ctx context.Context, p *models.Post) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creates++
+	if f.err != nil {
+		return f.err
+	}
+	p.ID = fmt.Sprintf("post-%d", len(f.posts)+1)
+	saved := *p
+	f.posts = append(f.posts, &saved)
+	return nil
+}
+
+// GetAll retorna copias de las publicaciones guardadas, en el orden en que se agregaron.
+func (f *fakePostRepo) GetAll(ctx context.Context, filter models.PostFilter) ([]*models.Post, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	posts := make([]*models.Post, 0, len(f.posts))
+	for _, p := range f.posts {
+		saved := *p
+		posts = append(posts, &saved)
+	}
+	return posts, nil
+}
+
+// SlugInUse indica si alguna publicación guardada usa el slug.
+func (f *fakePostRepo) SlugInUse(ctx context.Context, slug string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return false, f.err
+	}
+	for _, p := range f.posts {
+		if p.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeUploader imita a Cloudinary con Overwrite=false: guarda el contenido por PublicID y
+// rechaza los PublicID ya usados con ErrPublicIDTaken.
+type fakeUploader struct {
+	mu        sync.Mutex
+	images    map[string][]byte
+	uploads   []service.ImageUploadParams
+	destroyed []string
+	// taken son PublicID que se tratan como ya usados por otra imagen; "*" los incluye a todos.
+	taken map[string]bool
+	// err hace fallar todas las subidas.
+	err error
+}
+
+func newFakeUploader() *fakeUploader {
+	return &fakeUploader{images: make(map[string][]byte), taken: make(map[string]bool)}
+}
+
+func (f *fakeUploader) Upload(ctx context.Context, file io.Reader, params service.ImageUploadParams) (*service.ImageUploadResult, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uploads = append(f.uploads, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	id := params.Folder + "/" + params.PublicID
+	if _, used := f.images[id]; (used || f.taken[params.PublicID] || f.taken["*"]) && !params.Overwrite {
+		return nil, fmt.Errorf("%w: %q", service.ErrPublicIDTaken, id)
+	}
+	f.images[id] = data
+	return &service.ImageUploadResult{PublicID: id, SecureURL: "https://res.cloudinary.test/" + id + ".png", Format: "png"}, nil
+}
+
+func (f *fakeUploader) Destroy(ctx context.Context, publicID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.destroyed = append(f.destroyed, publicID)
+	delete(f.images, publicID)
+	return nil
+}
+
+// noFlags deja todas las funcionalidades en su valor por defecto.
+type noFlags struct{}
+
+func (noFlags) Lookup(string) (bool, bool) { return false, false }
+
+func newTestPostController(repo usecases.PostRepository, uploader service.ImageUploader) *PostController {
+	flags := features.New(noFlags{})
+	posts := usecases.NewPostUsecase(repo, flags, usecases.PostUsecaseOptions{ReadingWPM: 200})
+	cfg := config.UploadConfig{
+		MaxRequestBytes:      1 << 20,
+		MultipartMemoryBytes: 1 << 20,
+		MaxImageWidth:        100,
+		MaxImageHeight:       100,
+		AllowedExtensions:    []string{"png"},
+	}
+	return NewPostController(posts, nil, nil, uploader, nil, cfg, flags)
+}
+
+// testPNG retorna una imagen PNG de size x size píxeles.
+func testPNG(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newCreateRequest arma un POST /public/posts multipart con título, contenido y, si img no es
+// nil, la imagen en el campo "image".
+func newCreateRequest(t *testing.T, title string, img []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", title)
+	mw.WriteField("content", "contenido de "+title)
+	if img != nil {
+		part, err := mw.CreateFormFile("image", "foto.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(img)
+	}
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/public/posts", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+type createdPost struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	ImageURL string `json:"imageUrl"`
+}
+
+func TestCreateConcurrentPostsKeepTheirOwnImage(t *testing.T) {
+	repo := &fakePostRepo{}
+	uploader := newFakeUploader()
+	c := newTestPostController(repo, uploader)
+
+	// imágenes de distinto tamaño para distinguir cuál quedó en cada publicación
+	images := map[string][]byte{"uno": testPNG(t, 2), "dos": testPNG(t, 3)}
+	responses := make(map[string]*httptest.ResponseRecorder, len(images))
+	var wg sync.WaitGroup
+	for title, img := range images {
+		w := httptest.NewRecorder()
+		responses[title] = w
+		r := newCreateRequest(t, title, img)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Create(w, r)
+		}()
+	}
+	wg.Wait()
+
+	urls := make(map[string]bool)
+	for title, w := range responses {
+		if w.Code != http.StatusCreated {
+			t.Fatalf("%s: status = %d, body = %s", title, w.Code, w.Body)
+		}
+		var created createdPost
+		if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+			t.Fatal(err)
+		}
+		urls[created.ImageURL] = true
+	}
+	if len(urls) != len(images) {
+		t.Fatalf("las publicaciones comparten la URL de la imagen: %v", urls)
+	}
+
+	if len(repo.posts) != len(images) {
+		t.Fatalf("publicaciones guardadas = %d, se esperaban %d", len(repo.posts), len(images))
+	}
+	if repo.posts[0].ImagePublicID == repo.posts[1].ImagePublicID {
+		t.Fatalf("ambas publicaciones usan el PublicID %q", repo.posts[0].ImagePublicID)
+	}
+	for _, p := range repo.posts {
+		if got := uploader.images[p.ImagePublicID]; !bytes.Equal(got, images[p.Title]) {
+			t.Errorf("%s: la imagen guardada con %s no es la que se subió", p.Title, p.ImagePublicID)
+		}
+		if want := "https://res.cloudinary.test/" + p.ImagePublicID + ".png"; p.ImageURL != want {
+			t.Errorf("%s: ImageURL = %q, se esperaba %q", p.Title, p.ImageURL, want)
+		}
+	}
+	for _, params := range uploader.uploads {
+		if params.Overwrite {
+			t.Errorf("subida con Overwrite=true: %+v", params)
+		}
+	}
+}
+
+func TestCreateRetriesOnPublicIDTaken(t *testing.T) {
+	repo := &fakePostRepo{}
+	uploader := newFakeUploader()
+	c := newTestPostController(repo, uploader)
+
+	// el primer PublicID que se pida ya está en uso
+	c.uploader = &onceTakenUploader{fakeUploader: uploader}
+
+	w := httptest.NewRecorder()
+	c.Create(w, newCreateRequest(t, "uno", testPNG(t, 2)))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if len(uploader.uploads) != 2 {
+		t.Fatalf("subidas = %d, se esperaba un reintento", len(uploader.uploads))
+	}
+	if uploader.uploads[0].PublicID == uploader.uploads[1].PublicID {
+		t.Fatalf("el reintento repitió el PublicID %q", uploader.uploads[0].PublicID)
+	}
+	if got := repo.posts[0].ImagePublicID; got != "posts_images/"+uploader.uploads[1].PublicID {
+		t.Fatalf("ImagePublicID = %q, se esperaba el del reintento", got)
+	}
+}
+
+// onceTakenUploader marca como usado el primer PublicID que recibe.
+type onceTakenUploader struct {
+	*fakeUploader
+	once sync.Once
+}
+
+func (u *onceTakenUploader) Upload(ctx context.Context, file io.Reader, params service.ImageUploadParams) (*service.ImageUploadResult, error) {
+	u.once.Do(func() {
+		u.mu.Lock()
+		u.taken[params.PublicID] = true
+		u.mu.Unlock()
+	})
+	return u.fakeUploader.Upload(ctx, file, params)
+}
+
+func TestCreateReturnsConflictWhenRetryIsTaken(t *testing.T) {
+	repo := &fakePostRepo{}
+	uploader := newFakeUploader()
+	uploader.taken["*"] = true
+	c := newTestPostController(repo, uploader)
+
+	w := httptest.NewRecorder()
+	c.Create(w, newCreateRequest(t, "uno", testPNG(t, 2)))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, se esperaba 409", w.Code)
+	}
+	if len(uploader.uploads) != 2 {
+		t.Fatalf("subidas = %d, se esperaban 2 (intento y reintento)", len(uploader.uploads))
+	}
+	if repo.creates != 0 {
+		t.Fatal("se guardó la publicación pese al conflicto")
+	}
+}
+
+func TestCreateDestroysImageWhenSaveFails(t *testing.T) {
+	repo := &fakePostRepo{err: errors.New("firestore no disponible")}
+	uploader := newFakeUploader()
+	c := newTestPostController(repo, uploader)
+
+	w := httptest.NewRecorder()
+	c.Create(w, newCreateRequest(t, "uno", testPNG(t, 2)))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, se esperaba 500", w.Code)
+	}
+	if len(uploader.uploads) != 1 {
+		t.Fatalf("subidas = %d, se esperaba 1", len(uploader.uploads))
+	}
+	want := "posts_images/" + uploader.uploads[0].PublicID
+	if len(uploader.destroyed) != 1 || uploader.destroyed[0] != want {
+		t.Fatalf("imágenes eliminadas = %v, se esperaba solo %s", uploader.destroyed, want)
+	}
+	if len(uploader.images) != 0 {
+		t.Fatalf("quedaron imágenes huérfanas: %d", len(uploader.images))
+	}
+}
+
+func TestCreateDoesNotDestroyWhenUploadFails(t *testing.T) {
+	repo := &fakePostRepo{}
+	uploader := newFakeUploader()
+	uploader.err = errors.New("cloudinary no disponible")
+	c := newTestPostController(repo, uploader)
+
+	w := httptest.NewRecorder()
+	c.Create(w, newCreateRequest(t, "uno", testPNG(t, 2)))
+
+	if w.Code < 500 {
+		t.Fatalf("status = %d, se esperaba un error del servidor", w.Code)
+	}
+	if len(uploader.destroyed) != 0 {
+		t.Fatalf("se eliminaron imágenes que no se subieron: %v", uploader.destroyed)
+	}
+	if repo.creates != 0 {
+		t.Fatal("se guardó la publicación pese a que la imagen no se subió")
+	}
+}
+
+// withRole deja en el contexto de r el token de un usuario autenticado con el rol indicado;
+// role vacío es un usuario sin rol.
+func withRole(r *http.Request, uid, role string) *http.Request {
+	token := &auth.Token{UID: uid, Claims: map[string]interface{}{}}
+	if role != "" {
+		token.Claims["role"] = role
+	}
+	return r.WithContext(context.WithValue(r.Context(), middleware.AuthUserKey, token))
+}
+
+func TestCreateKeepsCreatedAtOnlyForAdmins(t *testing.T) {
+	imported := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := `{"title":"importada","content":"contenido","createdAt":"2020-01-02T03:04:05Z"}`
+
+	tests := []struct {
+		name string
+		role string
+		anon bool
+		keep bool
+	}{
+		{name: "anónimo", anon: true},
+		{name: "usuario"},
+		{name: "moderador", role: "moderator"},
+		{name: "administrador", role: "admin", keep: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePostRepo{}
+			c := newTestPostController(repo, newFakeUploader())
+
+			r := httptest.NewRequest(http.MethodPost, "/public/posts", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			if !tt.anon {
+				r = withRole(r, "u1", tt.role)
+			}
+			before := time.Now()
+			w := httptest.NewRecorder()
+			c.Create(w, r)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			got := repo.posts[0].CreatedAt
+			if tt.keep && !got.Equal(imported) {
+				t.Fatalf("CreatedAt = %v, se esperaba la fecha enviada %v", got, imported)
+			}
+			if !tt.keep && got.Before(before) {
+				t.Fatalf("CreatedAt = %v, se esperaba la hora del servidor e ignorar la enviada", got)
+			}
+		})
+	}
+}
+
+func TestCreateMultipartIgnoresCreatedAtFromNonAdmins(t *testing.T) {
+	repo := &fakePostRepo{}
+	c := newTestPostController(repo, newFakeUploader())
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "importada")
+	mw.WriteField("content", "contenido")
+	mw.WriteField("createdAt", "2020-01-02T03:04:05Z")
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/public/posts", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	before := time.Now()
+	w := httptest.NewRecorder()
+	c.Create(w, withRole(r, "u1", ""))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if got := repo.posts[0].CreatedAt; got.Before(before) {
+		t.Fatalf("CreatedAt = %v, se esperaba la hora del servidor", got)
+	}
+}
+
+func TestSlugAvailable(t *testing.T) {
+	repo := &fakePostRepo{posts: []*models.Post{{ID: "post-1", Slug: "mi-primer-post"}}}
+	c := newTestPostController(repo, newFakeUploader())
+
+	tests := []struct {
+		slug      string
+		status    int
+		want      string
+		available bool
+	}{
+		{slug: "otro-post", status: http.StatusOK, want: "otro-post", available: true},
+		{slug: "mi-primer-post", status: http.StatusOK, want: "mi-primer-post"},
+		// se normaliza como al crear la publicación antes de buscarlo
+		{slug: "  Mi Primer_Pôst ", status: http.StatusOK, want: "mi-primer-post"},
+		{slug: "suggest", status: http.StatusOK, want: "suggest"},
+		{slug: "mi/post", status: http.StatusBadRequest},
+		{slug: "¿post?", status: http.StatusBadRequest},
+		{slug: strings.Repeat("a", 81), status: http.StatusBadRequest},
+		{slug: "   ", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			target := "/public/posts/slug-available?slug=" + url.QueryEscape(tt.slug)
+			w := httptest.NewRecorder()
+			c.SlugAvailable(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, se esperaba %d; body = %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var got SlugAvailability
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Slug != tt.want || got.Available != tt.available {
+				t.Errorf("respuesta = %+v, se esperaba slug %q y available %v", got, tt.want, tt.available)
+			}
+		})
+	}
+}
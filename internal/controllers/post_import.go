@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+const (
+	// maxImportRecords es el número máximo de publicaciones aceptadas en una importación.
+	maxImportRecords = 1000
+	// importBatchSize es cuántas publicaciones válidas se acumulan antes de guardarlas, para
+	// no mantener toda la importación en memoria.
+	importBatchSize = 100
+	// maxImportBytes es el tamaño máximo del cuerpo de una importación.
+	maxImportBytes = 10 << 20
+)
+
+// ImportPostRequest es una publicación migrada desde otra plataforma. La imagen debe estar
+// alojada previamente: solo se guarda su URL.
+type ImportPostRequest struct {
+	Title     string     `json:"title"      validate:"required,max=200"`
+	Content   string     `json:"content"    validate:"required,max=10000"`
+	AuthorID  string     `json:"authorId"`
+	Tags      []string   `json:"tags"`
+	ImageURL  string     `json:"imageUrl"  validate:"omitempty,url"`
+	CreatedAt *time.Time `json:"createdAt" validate:"omitempty,lte"`
+}
+
+// ImportResult es el resultado de importar la publicación en la posición Index del arreglo.
+type ImportResult struct {
+	Index  int               `json:"index"`
+	ID     string            `json:"id,omitempty"`
+	Error  string            `json:"error,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ImportResponse resume una importación. Si la lectura del arreglo se interrumpe, Error indica
+// el motivo y Results contiene las publicaciones procesadas hasta ese punto, que sí se guardaron.
+type ImportResponse struct {
+	Imported int            `json:"imported"`
+	Failed   int            `json:"failed"`
+	Results  []ImportResult `json:"results"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// @Summary Importar publicaciones
+// @Description Importa un arreglo JSON de publicaciones migradas desde otra plataforma (máximo 1000), conservando su autor y fecha de creación. Cada registro se valida por separado y el resultado indica, por posición, el ID creado o el error. Las imágenes no se suben: se guarda la URL recibida. Solo para administradores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param posts body []ImportPostRequest true "Publicaciones a importar"
+// @Success 200 {object} ImportResponse "Resultado por publicación"
+// @Failure 400 {object} ImportResponse "El cuerpo no es un arreglo JSON válido"
+// @Failure 413 {object} ImportResponse "Demasiadas publicaciones o cuerpo demasiado grande"
+// @Router /admin/posts/import [post]
+func (c *PostController) Import(w http.ResponseWriter, r *http.Request) {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		httputil.WriteError(w, http.StatusBadRequest, "Se esperaba un arreglo JSON de publicaciones")
+		return
+	}
+
+	resp := ImportResponse{Results: make([]ImportResult, 0)}
+	batch := make([]*models.Post, 0, importBatchSize)
+	batchIdx := make([]int, 0, importBatchSize)
+	flush := func() {
+		for i, err := range c.postUsecase.ImportPosts(r.Context(), batch) {
+			res := ImportResult{Index: batchIdx[i]}
+			if err != nil {
+				log.Printf("Error importando publicación %d: %v", batchIdx[i], err)
+				res.Error = err.Error()
+				var typeErr *usecases.PostValidationError
+				if errors.As(err, &typeErr) {
+					res.Fields = typeErr.Fields
+				}
+				resp.Failed++
+			} else {
+				res.ID = batch[i].ID
+				resp.Imported++
+			}
+			resp.Results = append(resp.Results, res)
+		}
+		batch = batch[:0]
+		batchIdx = batchIdx[:0]
+	}
+	respond := func(status int, errMsg string) {
+		if len(batch) > 0 {
+			flush()
+		}
+		sort.Slice(resp.Results, func(i, j int) bool { return resp.Results[i].Index < resp.Results[j].Index })
+		resp.Error = errMsg
+		httputil.WriteJSON(w, status, resp)
+	}
+
+	for index := 0; dec.More(); index++ {
+		if index == maxImportRecords {
+			respond(http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("Máximo %d publicaciones por importación; el resto no se procesó", maxImportRecords))
+			return
+		}
+
+		var req ImportPostRequest
+		if err := dec.Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respond(http.StatusRequestEntityTooLarge, "La importación supera el tamaño máximo permitido; el resto no se procesó")
+				return
+			}
+			respond(http.StatusBadRequest, fmt.Sprintf("Publicación %d: JSON inválido: %v; el resto no se procesó", index, err))
+			return
+		}
+
+		fields, err := validationFields(req)
+		if err != nil || len(fields) > 0 {
+			resp.Results = append(resp.Results, ImportResult{Index: index, Error: "La publicación no es válida", Fields: fields})
+			resp.Failed++
+			continue
+		}
+
+		post := &models.Post{
+			Title:    req.Title,
+			Content:  req.Content,
+			AuthorID: req.AuthorID,
+			Tags:     req.Tags,
+			ImageURL: req.ImageURL,
+		}
+		if req.CreatedAt != nil {
+			post.CreatedAt = *req.CreatedAt
+		}
+		batch = append(batch, post)
+		batchIdx = append(batchIdx, index)
+		if len(batch) == importBatchSize {
+			flush()
+		}
+	}
+	respond(http.StatusOK, "")
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// streamFlushEvery es cada cuántas publicaciones se envía al cliente lo escrito hasta el momento.
+const streamFlushEvery = 100
+
+// @Summary Exportar publicaciones como NDJSON
+// @Description Transmite todas las publicaciones, sin importar su visibilidad, como JSON delimitado por saltos de línea: una publicación por línea. La respuesta se envía de a poco para que el consumidor procese las publicaciones a medida que llegan. Acepta el mismo orden y el mismo filtro hasImage que GET /public/posts; con views se usan las vistas ya guardadas. Si falla a mitad de la transmisión se corta la conexión, para que el consumidor no tome la exportación truncada por completa. Solo para administradores.
+// @Tags Admin
+// @Produce application/x-ndjson
+// @Param sort query string false "Orden: recent (por defecto), views u oldest"
+// @Param hasImage query bool false "true deja solo las publicaciones con imagen; false, solo las que no tienen"
+// @Success 200 {object} models.Post "Una publicación por línea"
+// @Failure 400 {object} map[string]string "Orden o filtro inválido"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Permisos insuficientes"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/posts/stream [get]
+func (c *PostController) Stream(w http.ResponseWriter, r *http.Request) {
+	order, ok := parsePostSort(w, r)
+	if !ok {
+		return
+	}
+	hasImage, ok := parseHasImage(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+	written := 0
+
+	err := c.postUsecase.StreamPosts(r.Context(), order, hasImage, func(p *models.Post) error {
+		if err := enc.Encode(p); err != nil {
+			return err
+		}
+		written++
+		if written%streamFlushEvery == 0 {
+			return rc.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error transmitiendo publicaciones tras %d: %v", written, err)
+		if written == 0 {
+			httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+			return
+		}
+		// con la respuesta ya iniciada solo queda cortar la conexión, para que el consumidor no
+		// confunda la exportación truncada con una completa
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+// streamRepo recorre las publicaciones guardadas en el orden en que se agregaron. Si failAfter
+// es positivo, falla tras entregar esa cantidad.
+type streamRepo struct {
+	fakePostRepo
+	failAfter int
+}
+
+func (f *streamRepo) Each(ctx context.Context, byViews, oldestFirst bool, fn func(*models.Post) error) error {
+	posts, err := f.GetAll(ctx, models.PostFilter{})
+	if err != nil {
+		return err
+	}
+	for i, p := range posts {
+		if f.failAfter > 0 && i == f.failAfter {
+			return errors.New("firestore no disponible")
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hideAll oculta a todos los usuarios los autores indicados, como si tuvieran un bloqueo.
+type hideAll []string
+
+func (h hideAll) Hidden(ctx context.Context, userID string) ([]string, error) { return h, nil }
+
+// newTestStreamController sirve repo con un bloqueo entre el administrador y "bloqueado".
+func newTestStreamController(repo *streamRepo) *PostController {
+	flags := features.New(noFlags{})
+	posts := usecases.NewPostUsecase(repo, flags, usecases.PostUsecaseOptions{ReadingWPM: 200, AuthorBlocks: hideAll{"bloqueado"}})
+	return NewPostController(posts, nil, nil, nil, nil, config.UploadConfig{}, flags)
+}
+
+// streamedIDs retorna los IDs de las publicaciones de una respuesta NDJSON.
+func streamedIDs(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var ids []string
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var p struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &p); err != nil {
+			t.Fatalf("línea %q: %v", sc.Text(), err)
+		}
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestStreamIgnoresExporterBlocks(t *testing.T) {
+	repo := &streamRepo{}
+	repo.posts = []*models.Post{
+		{ID: "p1", AuthorID: "u1"},
+		{ID: "p2", AuthorID: "bloqueado"},
+		{ID: "p3", AuthorID: "u1", Visibility: models.VisibilityPrivate},
+	}
+	c := newTestStreamController(repo)
+
+	w := httptest.NewRecorder()
+	c.Stream(w, withRole(httptest.NewRequest(http.MethodGet, "/admin/posts/stream", nil), "admin-1", middleware.RoleAdmin))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if ids := streamedIDs(t, w); len(ids) != 3 {
+		t.Fatalf("se exportaron %v, se esperaban las 3 publicaciones", ids)
+	}
+}
+
+func TestStreamFiltersByHasImage(t *testing.T) {
+	repo := &streamRepo{}
+	repo.posts = []*models.Post{
+		{ID: "con-imagen", AuthorID: "u1", ImageURL: "https://img/1.png"},
+		{ID: "sin-imagen", AuthorID: "u1"},
+	}
+	c := newTestStreamController(repo)
+
+	w := httptest.NewRecorder()
+	c.Stream(w, withRole(httptest.NewRequest(http.MethodGet, "/admin/posts/stream?hasImage=true", nil), "admin-1", middleware.RoleAdmin))
+	if ids := streamedIDs(t, w); len(ids) != 1 || ids[0] != "con-imagen" {
+		t.Fatalf("hasImage=true exportó %v", ids)
+	}
+
+	w = httptest.NewRecorder()
+	c.Stream(w, withRole(httptest.NewRequest(http.MethodGet, "/admin/posts/stream?hasImage=no", nil), "admin-1", middleware.RoleAdmin))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("hasImage inválido: status = %d, se esperaba 400", w.Code)
+	}
+}
+
+func TestStreamAbortsWhenFailingMidway(t *testing.T) {
+	repo := &streamRepo{failAfter: 2}
+	repo.posts = []*models.Post{
+		{ID: "p1", AuthorID: "u1"},
+		{ID: "p2", AuthorID: "u1"},
+		{ID: "p3", AuthorID: "u1"},
+	}
+	c := newTestStreamController(repo)
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Fatalf("recover() = %v, se esperaba http.ErrAbortHandler", rec)
+		}
+	}()
+	c.Stream(httptest.NewRecorder(), withRole(httptest.NewRequest(http.MethodGet, "/admin/posts/stream", nil), "admin-1", middleware.RoleAdmin))
+	t.Fatal("la transmisión truncada terminó sin cortar la conexión")
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// maxStreamFieldBytes limita cada campo de texto del formulario cuando la imagen se transmite,
+// ya que esos campos se leen completos en memoria antes de llegar a la imagen.
+const maxStreamFieldBytes = 64 << 10
+
+// createStreaming crea una publicación leyendo el formulario multipart parte por parte, de modo
+// que la imagen pasa del cuerpo de la petición a Cloudinary sin guardarse completa en memoria
+// ni en disco. Los campos de texto deben enviarse antes que la imagen, que debe ser el último.
+// Como la imagen no puede volver a leerse, no se reintenta la subida ni se difiere si
+// Cloudinary falla.
+func (c *PostController) createStreaming(w http.ResponseWriter, r *http.Request) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Error parsing form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	values := url.Values{}
+	var image io.Reader
+	var filename string
+	for image == nil {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			c.writeFormError(w, err)
+			return
+		}
+		if part.FormName() == "image" {
+			image, filename = part, part.FileName()
+			continue
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxStreamFieldBytes+1))
+		if err != nil {
+			c.writeFormError(w, err)
+			return
+		}
+		if len(value) > maxStreamFieldBytes {
+			http.Error(w, fmt.Sprintf("El campo %s supera el máximo de %d KB", part.FormName(), maxStreamFieldBytes>>10),
+				http.StatusRequestEntityTooLarge)
+			return
+		}
+		values.Add(part.FormName(), string(value))
+	}
+
+	req, ok := formPostRequest(w, r, values.Get)
+	if !ok {
+		return
+	}
+	if image == nil {
+		c.savePost(w, r, req, uploadedImage{})
+		return
+	}
+
+	image, ok = checkImageStream(w, image, filename, c.uploadCfg)
+	if !ok {
+		return
+	}
+	res, err := c.uploader.Upload(r.Context(), image, c.postUploadParams())
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.writeFormError(w, err)
+		return
+	}
+	if !writeUploadError(w, err) {
+		return
+	}
+
+	// la imagen ya se subió: si el formulario sigue, se descarta para no guardar una
+	// publicación con campos ignorados
+	if _, err := mr.NextPart(); err != io.EOF {
+		service.DiscardUpload(r.Context(), c.uploader, res.PublicID)
+		http.Error(w, "image debe ser el último campo del formulario", http.StatusBadRequest)
+		return
+	}
+
+	c.savePost(w, r, req, uploadedImageFrom(res))
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+func TestCreateStreamingDestroysImageWhenSaveFails(t *testing.T) {
+	repo := &fakePostRepo{err: errors.New("firestore no disponible")}
+	uploader := newFakeUploader()
+	c := newTestPostController(repo, uploader)
+	c.uploadCfg.StreamUploads = true
+
+	w := httptest.NewRecorder()
+	c.Create(w, newCreateRequest(t, "uno", testPNG(t, 2)))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if len(uploader.uploads) != 1 {
+		t.Fatalf("subidas = %d, se esperaba 1", len(uploader.uploads))
+	}
+	if want := "posts_images/" + uploader.uploads[0].PublicID; len(uploader.destroyed) != 1 || uploader.destroyed[0] != want {
+		t.Fatalf("imágenes eliminadas = %v, se esperaba solo %s", uploader.destroyed, want)
+	}
+}
+
+func TestCreateStreamingDoesNotDestroyWhenUploadFails(t *testing.T) {
+	repo := &fakePostRepo{}
+	uploader := newFakeUploader()
+	uploader.err = errors.New("cloudinary no disponible")
+	c := newTestPostController(repo, uploader)
+	c.uploadCfg.StreamUploads = true
+
+	w := httptest.NewRecorder()
+	c.Create(w, newCreateRequest(t, "uno", testPNG(t, 2)))
+
+	if w.Code < 500 {
+		t.Fatalf("status = %d, se esperaba un error del servidor", w.Code)
+	}
+	if len(uploader.destroyed) != 0 {
+		t.Fatalf("se eliminaron imágenes que no se subieron: %v", uploader.destroyed)
+	}
+	if repo.creates != 0 {
+		t.Fatal("se guardó la publicación pese a que la imagen no se subió")
+	}
+}
+
+// discardUploader lee la imagen sin guardarla, para que el benchmark mida solo la memoria que
+// usa el controlador al recibirla.
+type discardUploader struct{ fakeUploader }
+
+func (d *discardUploader) Upload(ctx context.Context, file io.Reader, params service.ImageUploadParams) (*service.ImageUploadResult, error) {
+	if _, err := io.Copy(io.Discard, file); err != nil {
+		return nil, err
+	}
+	id := params.Folder + "/" + params.PublicID
+	return &service.ImageUploadResult{PublicID: id, SecureURL: "https://res.cloudinary.test/" + id + ".png", Format: "png"}, nil
+}
+
+// BenchmarkCreateUpload compara la memoria de crear una publicación con una imagen de ~8 MB
+// al leer el formulario con ParseMultipartForm frente a transmitir la imagen a Cloudinary, con los
+// límites por defecto de config.LoadUploadConfig.
+func BenchmarkCreateUpload(b *testing.B) {
+	var img bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := enc.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 1448, 1448))); err != nil {
+		b.Fatal(err)
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "benchmark")
+	mw.WriteField("content", "contenido")
+	part, _ := mw.CreateFormFile("image", "foto.png")
+	part.Write(img.Bytes())
+	mw.Close()
+
+	for _, stream := range []bool{false, true} {
+		name := "buffered"
+		if stream {
+			name = "streaming"
+		}
+		b.Run(name, func(b *testing.B) {
+			c := newTestPostController(&fakePostRepo{}, &discardUploader{})
+			c.uploadCfg.MaxRequestBytes = 20 << 20
+			c.uploadCfg.MultipartMemoryBytes = 10 << 20
+			c.uploadCfg.MaxImageWidth, c.uploadCfg.MaxImageHeight = 2000, 2000
+			c.uploadCfg.StreamUploads = stream
+
+			b.ReportAllocs()
+			b.SetBytes(int64(body.Len()))
+			for b.Loop() {
+				r := httptest.NewRequest(http.MethodPost, "/public/posts", bytes.NewReader(body.Bytes()))
+				r.Header.Set("Content-Type", mw.FormDataContentType())
+				w := httptest.NewRecorder()
+				c.Create(w, r)
+				if w.Code != http.StatusCreated {
+					b.Fatalf("status = %d, body = %s", w.Code, w.Body)
+				}
+				if r.MultipartForm != nil {
+					r.MultipartForm.RemoveAll()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+)
+
+// @Summary Publicaciones por varias etiquetas
+// @Description Lista, de la más reciente a la más antigua, las publicaciones que tienen todas las etiquetas indicadas (mode=all) o al menos una (mode=any, por defecto). Se admiten hasta 10 etiquetas, que se comparan sin distinguir mayúsculas. Las publicaciones privadas o para seguidores solo se incluyen si quien consulta (autenticación opcional) es su autor.
+// @Tags Post
+// @Produce json
+// @Param tags query string true "Etiquetas separadas por coma"
+// @Param mode query string false "all o any (por defecto)"
+// @Param include query string false "author incluye los datos mínimos del autor de cada publicación"
+// @Param limit query int false "Resultados por página (por defecto 50, máximo 200)"
+// @Param offset query int false "Número de resultados a omitir"
+// @Success 200 {array} models.Post "Publicaciones con las etiquetas"
+// @Header 200 {string} Link "Enlaces a las páginas first, prev, next y last (RFC 8288)"
+// @Header 200 {int} X-Total-Count "Total de publicaciones encontradas"
+// @Failure 400 {object} map[string]string "Etiquetas, modo o paginación inválidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/tags [get]
+func (c *PostController) GetByTags(w http.ResponseWriter, r *http.Request) {
+	var matchAll bool
+	switch r.URL.Query().Get("mode") {
+	case "", "any":
+	case "all":
+		matchAll = true
+	default:
+		httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'mode' debe ser 'all' o 'any'")
+		return
+	}
+	limit, offset, err := parsePagination(r, 50, maxPageSize)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tags := strings.Split(r.URL.Query().Get("tags"), ",")
+	posts, total, err := c.postUsecase.GetPostsByTags(r.Context(), tags, matchAll, viewerID(r), limit, offset)
+	if errors.Is(err, usecases.ErrInvalidTags) {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo publicaciones por etiquetas: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	if !c.embedIncludes(w, r, posts) {
+		return
+	}
+	writePaginationHeaders(w, r, limit, offset, total)
+
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
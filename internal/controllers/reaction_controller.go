@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// ReactionController maneja las reacciones a publicaciones.
+type ReactionController struct {
+	usecase *usecases.ReactionUsecase
+}
+
+// NewReactionController crea un nuevo controlador de reacciones.
+func NewReactionController(usecase *usecases.ReactionUsecase) *ReactionController {
+	return &ReactionController{usecase: usecase}
+}
+
+// @Summary Reaccionar a una publicación
+// @Description Registra la reacción del usuario autenticado (like, dislike, love, laugh, angry o sad). Cada usuario tiene una sola reacción por publicación: reaccionar de nuevo la reemplaza. Like y dislike siguen contándose en los campos likes y dislikes.
+// @Tags Post
+// @Produce json
+// @Param id path string true "ID de la publicación"
+// @Param type query string true "Tipo de reacción"
+// @Success 200 {object} map[string]int "Totales por reacción"
+// @Failure 400 {object} map[string]string "Tipo de reacción inválido"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Hay un bloqueo entre el usuario y el autor"
+// @Failure 404 {object} map[string]string "Publicación no encontrada o que el usuario no puede ver"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/{id}/react [post]
+func (c *ReactionController) React(w http.ResponseWriter, r *http.Request) {
+	token, ok := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+	if !ok {
+		httputil.WriteError(w, http.StatusUnauthorized, "Token no encontrado")
+		return
+	}
+
+	tallies, err := c.usecase.React(r.Context(), token.UID, mux.Vars(r)["id"], r.URL.Query().Get("type"))
+	switch {
+	case errors.Is(err, usecases.ErrInvalidReaction):
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, usecases.ErrPostNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	case errors.Is(err, usecases.ErrBlocked):
+		httputil.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		log.Printf("Error registrando reacción: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, tallies)
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// RepostController maneja las peticiones HTTP relacionadas a reposts.
+type RepostController struct {
+	usecase *usecases.RepostUsecase
+	flags   *features.Flags
+}
+
+// NewRepostController crea un nuevo controlador de reposts.
+func NewRepostController(usecase *usecases.RepostUsecase, flags *features.Flags) *RepostController {
+	return &RepostController{usecase: usecase, flags: flags}
+}
+
+type CreateRepostRequest struct {
+	Comment string `json:"comment"`
+}
+
+// @Summary Compartir una publicación
+// @Description Crea un repost de la publicación indicada para el usuario autenticado, con un comentario opcional.
+// @Tags Repost
+// @Accept json
+// @Produce json
+// @Param id path string true "ID de la publicación original"
+// @Param repost body CreateRepostRequest false "Comentario del repost"
+// @Success 201 {object} models.Repost "Repost creado exitosamente"
+// @Failure 400 {object} map[string]string "Solicitud inválida"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Hay un bloqueo entre el usuario y el autor"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno al crear el repost"
+// @Router /api/posts/{id}/repost [post]
+func (c *RepostController) Create(w http.ResponseWriter, r *http.Request) {
+	if !c.flags.IsEnabled(features.Reposts) {
+		httputil.WriteError(w, http.StatusNotFound, "Funcionalidad no disponible")
+		return
+	}
+
+	token, ok := r.Context().Value(middleware.AuthUserKey).(*auth.Token)
+	if !ok {
+		httputil.WriteError(w, http.StatusUnauthorized, "Token no encontrado")
+		return
+	}
+
+	var req CreateRepostRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, "Solicitud inválida")
+			return
+		}
+	}
+
+	repost, err := c.usecase.CreateRepost(r.Context(), token.UID, mux.Vars(r)["id"], req.Comment)
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	}
+	if errors.Is(err, usecases.ErrBlocked) {
+		httputil.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error creando repost: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "No se pudo crear el repost")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, repost)
+}
+
+// @Summary Listar los reposts de un usuario
+// @Description Retorna los reposts del usuario, cada uno con el contenido actual de la publicación original.
+// @Tags Repost
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Success 200 {array} models.Repost "Lista de reposts"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/reposts [get]
+func (c *RepostController) GetByUser(w http.ResponseWriter, r *http.Request) {
+	reposts, err := c.usecase.GetUserReposts(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error obteniendo reposts: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, reposts)
+}
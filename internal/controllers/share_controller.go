@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// ogDescriptionSize es el largo máximo del resumen usado como og:description.
+const ogDescriptionSize = 200
+
+// ShareController responde los metadatos que usan las redes sociales y clientes de mensajería
+// para mostrar una vista previa de una publicación compartida.
+type ShareController struct {
+	postUsecase *usecases.PostUsecase
+	siteURL     string
+	// defaultImageURL se usa como og:image en las publicaciones sin imagen; vacío la omite.
+	defaultImageURL string
+}
+
+// NewShareController crea el controlador. siteURL es la URL base de los enlaces a las
+// publicaciones y defaultImageURL la imagen de las publicaciones que no tienen una.
+func NewShareController(u *usecases.PostUsecase, siteURL, defaultImageURL string) *ShareController {
+	return &ShareController{postUsecase: u, siteURL: siteURL, defaultImageURL: defaultImageURL}
+}
+
+// OpenGraphResponse son las etiquetas Open Graph de una publicación.
+type OpenGraphResponse struct {
+	Title       string `json:"og:title"`
+	Description string `json:"og:description"`
+	Image       string `json:"og:image,omitempty"`
+	URL         string `json:"og:url"`
+	Type        string `json:"og:type"`
+	SiteName    string `json:"og:site_name"`
+}
+
+var ogPage = template.Must(template.New("og").Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+{{- if .Image}}
+<meta property="og:image" content="{{.Image}}">
+<meta name="twitter:card" content="summary_large_image">
+{{- else}}
+<meta name="twitter:card" content="summary">
+{{- end}}
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:type" content="{{.Type}}">
+<meta property="og:site_name" content="{{.SiteName}}">
+<link rel="canonical" href="{{.URL}}">
+<meta http-equiv="refresh" content="0; url={{.URL}}">
+</head>
+<body><a href="{{.URL}}">{{.Title}}</a></body>
+</html>
+`))
+
+// @Summary Metadatos Open Graph de una publicación
+// @Description Retorna las etiquetas Open Graph (título, resumen del contenido, imagen y URL) para que los enlaces compartidos se muestren con vista previa. Con format=html, o si el cliente acepta text/html, responde una página mínima con las etiquetas que redirige a la publicación; si no, JSON. Las publicaciones sin imagen usan la imagen por defecto (OG_DEFAULT_IMAGE_URL). Acepta el ID o el slug, solo para publicaciones públicas, y no cuenta una vista.
+// @Tags Post
+// @Produce json
+// @Produce html
+// @Param id path string true "ID o slug de la publicación"
+// @Param format query string false "json o html; por defecto según la cabecera Accept"
+// @Success 200 {object} OpenGraphResponse "Etiquetas Open Graph"
+// @Failure 400 {object} map[string]string "Formato inválido"
+// @Failure 404 {object} map[string]string "Publicación no encontrada"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/{id}/og [get]
+func (c *ShareController) OpenGraph(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = "json"
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			format = "html"
+		}
+	case "json", "html":
+	default:
+		httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'format' debe ser 'json' o 'html'")
+		return
+	}
+
+	post, err := c.postUsecase.GetPublicPost(r.Context(), mux.Vars(r)["id"], viewerID(r))
+	if errors.Is(err, usecases.ErrPostNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Publicación no encontrada")
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo post para Open Graph: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	og := c.openGraph(post)
+	if format == "json" {
+		httputil.WriteJSON(w, http.StatusOK, og)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := ogPage.Execute(&buf, og); err != nil {
+		log.Printf("Error generando página Open Graph: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error formateando datos")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// openGraph arma las etiquetas de la publicación. Las publicaciones sin título, como las
+// preguntas, usan el inicio del contenido.
+func (c *ShareController) openGraph(p *models.Post) OpenGraphResponse {
+	title := p.Title
+	if title == "" {
+		title = usecases.Excerpt(p.Content, 80)
+	}
+	image := p.ImageURL
+	if p.ImageFallbackURL != "" {
+		// no todas las plataformas muestran WebP
+		image = p.ImageFallbackURL
+	}
+	if image == "" {
+		image = c.defaultImageURL
+	}
+	ref := p.Slug
+	if ref == "" {
+		ref = p.ID
+	}
+	return OpenGraphResponse{
+		Title:       title,
+		Description: usecases.Excerpt(p.Content, ogDescriptionSize),
+		Image:       image,
+		URL:         c.siteURL + "/posts/" + ref,
+		Type:        "article",
+		SiteName:    feedTitle,
+	}
+}
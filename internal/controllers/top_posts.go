@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+)
+
+// topPeriods son los periodos admitidos por Top.
+var topPeriods = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// @Summary Publicaciones con más likes del periodo
+// @Description Lista las publicaciones públicas que más likes recibieron en el último día, semana o mes, según la fecha de cada like, para páginas tipo "lo mejor de la semana". A diferencia del contador likes, que acumula desde siempre, solo cuentan los likes del periodo que siguen vigentes. El ranking se recalcula cada 5 minutos e incluye hasta 500 publicaciones.
+// @Tags Post
+// @Produce json
+// @Param period query string false "day, week (por defecto) o month"
+// @Param include query string false "author incluye los datos mínimos del autor de cada publicación"
+// @Param limit query int false "Resultados por página (por defecto 50, máximo 200)"
+// @Param offset query int false "Número de resultados a omitir"
+// @Success 200 {array} models.Post "Publicaciones ordenadas por likes del periodo"
+// @Header 200 {string} Link "Enlaces a las páginas first, prev, next y last (RFC 8288)"
+// @Header 200 {int} X-Total-Count "Total de publicaciones del ranking"
+// @Failure 400 {object} map[string]string "Periodo o paginación inválidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/posts/top [get]
+func (c *PostController) Top(w http.ResponseWriter, r *http.Request) {
+	period := topPeriods["week"]
+	if raw := r.URL.Query().Get("period"); raw != "" {
+		var ok bool
+		if period, ok = topPeriods[raw]; !ok {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'period' debe ser 'day', 'week' o 'month'")
+			return
+		}
+	}
+	limit, offset, err := parsePagination(r, 50, maxPageSize)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	posts, total, err := c.postUsecase.TopPosts(r.Context(), period, limit, offset, viewerID(r))
+	if err != nil {
+		log.Printf("Error obteniendo publicaciones con más likes: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	if !c.embedIncludes(w, r, posts) {
+		return
+	}
+	writePaginationHeaders(w, r, limit, offset, total)
+
+	httputil.WriteJSON(w, http.StatusOK, posts)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+)
+
+const (
+	defaultTrendingWindow = 7 * 24 * time.Hour
+	minTrendingWindow     = time.Hour
+	maxTrendingWindow     = 30 * 24 * time.Hour
+
+	defaultTrendingTagsLimit = 10
+	maxTrendingTagsLimit     = 50
+)
+
+// @Summary Etiquetas en tendencia
+// @Description Retorna las etiquetas más usadas en las publicaciones públicas creadas dentro de la ventana indicada, de la más usada a la menos usada. A diferencia de un conteo histórico, refleja de qué se está hablando ahora. El ranking de cada ventana se recalcula cada minuto.
+// @Tags Post
+// @Produce json
+// @Param window query string false "Ventana hacia atrás desde ahora: horas (24h) o días (7d). Por defecto 7d, entre 1h y 30d"
+// @Param limit query int false "Número máximo de etiquetas (por defecto 10, máximo 50)"
+// @Success 200 {array} models.TagCount "Etiquetas con el número de publicaciones que las usan"
+// @Failure 400 {object} map[string]string "Parámetros inválidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/tags/trending [get]
+func (c *PostController) TrendingTags(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	window := defaultTrendingWindow
+	if raw := q.Get("window"); raw != "" {
+		var ok bool
+		if window, ok = parseWindow(raw); !ok || window < minTrendingWindow || window > maxTrendingWindow {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'window' debe estar entre 1h y 30d, por ejemplo 24h o 7d")
+			return
+		}
+	}
+
+	limit := defaultTrendingTagsLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'limit' debe ser un entero positivo")
+			return
+		}
+		limit = min(n, maxTrendingTagsLimit)
+	}
+
+	tags, err := c.postUsecase.TrendingTags(r.Context(), window, limit)
+	if err != nil {
+		log.Printf("Error obteniendo etiquetas en tendencia: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, tags)
+}
+
+// parseWindow interpreta una duración en días ("7d") o en el formato de time.ParseDuration ("24h").
+func parseWindow(raw string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err == nil
+	}
+	d, err := time.ParseDuration(raw)
+	return d, err == nil
+}
@@ -1,12 +1,15 @@
 package controllers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
 	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
 )
 
 // UserController maneja las peticiones HTTP relacionadas a usuarios.
@@ -31,7 +34,7 @@ func NewUserController(usecase *usecases.UserUsecase) *UserController {
 // @Failure 500 {object} map[string]string "Error interno del servidor"
 // @Router /public/users [get]
 func (c *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 	userID := r.URL.Query().Get("id")
 	if userID == "" {
 		http.Error(w, `{"error": "Se requiere el parámetro 'id'"}`, http.StatusBadRequest)
@@ -45,9 +48,167 @@ func (c *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(user); err != nil {
-		log.Printf("Error serializando respuesta: %v", err)
-		http.Error(w, `{"error": "Error formateando datos"}`, http.StatusInternalServerError)
+	httputil.WriteJSON(w, http.StatusOK, user)
+}
+
+// @Summary Obtener el perfil de un usuario con estadísticas
+// @Description Retorna el usuario junto con su número de publicaciones, likes recibidos, seguidores, seguidos y fecha de registro.
+// @Tags User
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Success 200 {object} models.UserProfile "Perfil del usuario"
+// @Failure 404 {object} map[string]string "Usuario no encontrado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/profile [get]
+func (c *UserController) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+
+	profile, err := c.usecase.GetProfile(r.Context(), userID)
+	if errors.Is(err, usecases.ErrUserNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Usuario no encontrado")
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo perfil: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, profile)
+}
+
+// LikesReceivedResponse contiene el total de likes recibidos por un usuario.
+type LikesReceivedResponse struct {
+	UserID        string `json:"userId"`
+	LikesReceived int64  `json:"likesReceived"`
+}
+
+// @Summary Obtener el total de likes recibidos por un usuario
+// @Description Retorna la suma de likes de todas las publicaciones del usuario. Es 0 si no tiene publicaciones.
+// @Tags User
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Success 200 {object} LikesReceivedResponse "Likes recibidos"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/stats/likes-received [get]
+func (c *UserController) GetLikesReceived(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+
+	likes, err := c.usecase.GetLikesReceived(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error obteniendo likes recibidos: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
 	}
+
+	httputil.WriteJSON(w, http.StatusOK, LikesReceivedResponse{UserID: userID, LikesReceived: likes})
+}
+
+// @Summary Consultar las estadísticas de las publicaciones de un usuario
+// @Description Retorna los totales de vistas, likes, dislikes y reposts de las publicaciones del usuario creadas en el periodo, junto con el detalle paginado de cada una, de la más reciente a la más antigua. Solo el propio autor o un administrador pueden consultarlas.
+// @Tags User
+// @Produce json
+// @Param id path string true "ID del usuario"
+// @Param from query string false "Fecha inicial de creación (YYYY-MM-DD)"
+// @Param to query string false "Fecha final de creación exclusiva (YYYY-MM-DD)"
+// @Param limit query int false "Publicaciones por página (por defecto 50, máximo 200)"
+// @Param offset query int false "Número de publicaciones a omitir"
+// @Success 200 {object} models.UserAnalytics "Estadísticas del periodo"
+// @Header 200 {string} Link "Enlaces a las páginas first, prev, next y last (RFC 8288)"
+// @Header 200 {int} X-Total-Count "Total de publicaciones del periodo"
+// @Failure 400 {object} map[string]string "Parámetros inválidos"
+// @Failure 401 {object} map[string]string "Token no encontrado"
+// @Failure 403 {object} map[string]string "Solo el autor o un administrador"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/analytics [get]
+func (c *UserController) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["id"]
+	if viewerID(r) != userID && !isAdmin(r) {
+		httputil.WriteError(w, http.StatusForbidden, "Solo el autor o un administrador pueden ver estas estadísticas")
+		return
+	}
+
+	limit, offset, err := parsePagination(r, 50, maxPageSize)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var from, to time.Time
+	q := r.URL.Query()
+	if raw := q.Get("from"); raw != "" {
+		if from, err = time.Parse(time.DateOnly, raw); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'from' debe tener formato YYYY-MM-DD")
+			return
+		}
+	}
+	if raw := q.Get("to"); raw != "" {
+		if to, err = time.Parse(time.DateOnly, raw); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, "El parámetro 'to' debe tener formato YYYY-MM-DD")
+			return
+		}
+	}
+
+	analytics, total, err := c.usecase.GetAnalytics(r.Context(), userID, from, to, limit, offset)
+	if err != nil {
+		log.Printf("Error obteniendo estadísticas de %s: %v", userID, err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	writePaginationHeaders(w, r, limit, offset, total)
+
+	httputil.WriteJSON(w, http.StatusOK, analytics)
+}
+
+// UserBatchRequest lista los usuarios a consultar, como máximo 100.
+type UserBatchRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// @Summary Obtener varios usuarios por ID
+// @Description Retorna en una sola consulta los datos públicos (sin correo) de hasta 100 usuarios, en el orden pedido. Los IDs que no existen se omiten. Pensado para mostrar los autores de una página de publicaciones.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param request body UserBatchRequest true "IDs de los usuarios"
+// @Success 200 {array} models.PublicUser "Usuarios encontrados"
+// @Failure 400 {object} map[string]string "Solicitud inválida"
+// @Failure 422 {object} ValidationErrorResponse "Lista de IDs vacía o demasiado larga"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/batch [post]
+func (c *UserController) GetBatch(w http.ResponseWriter, r *http.Request) {
+	var req UserBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Solicitud inválida")
+		return
+	}
+	if !validateRequest(w, req) {
+		return
+	}
+
+	users, err := c.usecase.GetUsers(r.Context(), req.IDs)
+	if err != nil {
+		log.Printf("Error obteniendo usuarios: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, users)
+}
+
+// @Summary Recalcular el karma de todos los usuarios
+// @Description Reconstruye desde cero el karma guardado de cada usuario a partir de los likes y dislikes de sus publicaciones. Solo para administradores.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} map[string]int "Número de usuarios actualizados"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /admin/users/karma/recompute [post]
+func (c *UserController) RecomputeKarma(w http.ResponseWriter, r *http.Request) {
+	updated, err := c.usecase.RecomputeKarma(r.Context())
+	if err != nil {
+		log.Printf("Error recalculando karma (%d usuarios actualizados): %v", updated, err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]int{"updated": updated})
 }
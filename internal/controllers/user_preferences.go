@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+// UpdatePreferencesRequest son las nuevas preferencias de listado del usuario.
+type UpdatePreferencesRequest struct {
+	MutedTags   []string `json:"mutedTags"   validate:"max=100,dive,max=30"`
+	MutedUsers  []string `json:"mutedUsers"  validate:"max=100,dive,max=128"`
+	DefaultSort string   `json:"defaultSort" validate:"omitempty,oneof=recent views oldest"`
+}
+
+// @Summary Obtener las preferencias del listado
+// @Description Retorna las preferencias del usuario autenticado para GET /public/posts: etiquetas y autores silenciados y orden por defecto. Sin configurar, no filtran nada.
+// @Tags User
+// @Produce json
+// @Success 200 {object} models.UserPreferences "Preferencias"
+// @Failure 401 {object} map[string]string "No autenticado"
+// @Failure 404 {object} map[string]string "Usuario no encontrado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /api/preferences [get]
+func (c *UserController) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := c.usecase.GetPreferences(r.Context(), viewerID(r))
+	if errors.Is(err, usecases.ErrUserNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Usuario no encontrado")
+		return
+	}
+	if err != nil {
+		log.Printf("Error obteniendo preferencias: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, prefs)
+}
+
+// @Summary Actualizar las preferencias del listado
+// @Description Reemplaza las preferencias del usuario autenticado. GET /public/posts omite las publicaciones con alguna etiqueta silenciada o de un autor silenciado y, si la petición no indica sort, usa defaultSort. Se admiten hasta 100 etiquetas y 100 usuarios.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param preferences body UpdatePreferencesRequest true "Nuevas preferencias"
+// @Success 200 {object} models.UserPreferences "Preferencias guardadas"
+// @Failure 400 {object} map[string]string "Solicitud inválida"
+// @Failure 401 {object} map[string]string "No autenticado"
+// @Failure 404 {object} map[string]string "Usuario no encontrado"
+// @Failure 422 {object} ValidationErrorResponse "Preferencias inválidas"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /api/preferences [put]
+func (c *UserController) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Solicitud inválida")
+		return
+	}
+	if !validateRequest(w, req) {
+		return
+	}
+
+	prefs, err := c.usecase.UpdatePreferences(r.Context(), viewerID(r), &models.UserPreferences{
+		MutedTags:   req.MutedTags,
+		MutedUsers:  req.MutedUsers,
+		DefaultSort: req.DefaultSort,
+	})
+	if errors.Is(err, usecases.ErrUserNotFound) {
+		httputil.WriteError(w, http.StatusNotFound, "Usuario no encontrado")
+		return
+	}
+	if err != nil {
+		log.Printf("Error guardando preferencias: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, prefs)
+}
+
+// @Summary Silenciar a un usuario
+// @Description Oculta las publicaciones del usuario indicado en los listados del usuario autenticado, sin dejar de seguirlo. Es privado: el usuario silenciado no recibe ningún aviso. A diferencia de bloquear, no le impide ver ni interactuar con las publicaciones de quien lo silencia. Se admiten hasta 100 usuarios silenciados.
+// @Tags User
+// @Param id path string true "ID del usuario a silenciar"
+// @Success 204 "Usuario silenciado"
+// @Failure 400 {object} map[string]string "No puedes silenciarte a ti mismo o alcanzaste el máximo"
+// @Failure 401 {object} map[string]string "No autenticado"
+// @Failure 404 {object} map[string]string "Usuario no encontrado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/mute [post]
+func (c *UserController) Mute(w http.ResponseWriter, r *http.Request) {
+	err := c.usecase.MuteUser(r.Context(), viewerID(r), mux.Vars(r)["id"])
+	c.writeMuteResult(w, err)
+}
+
+// @Summary Dejar de silenciar a un usuario
+// @Description Vuelve a mostrar las publicaciones del usuario indicado en los listados del usuario autenticado. No falla si no estaba silenciado.
+// @Tags User
+// @Param id path string true "ID del usuario silenciado"
+// @Success 204 "Usuario ya no silenciado"
+// @Failure 401 {object} map[string]string "No autenticado"
+// @Failure 404 {object} map[string]string "Usuario no encontrado"
+// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Router /public/users/{id}/mute [delete]
+func (c *UserController) Unmute(w http.ResponseWriter, r *http.Request) {
+	err := c.usecase.UnmuteUser(r.Context(), viewerID(r), mux.Vars(r)["id"])
+	c.writeMuteResult(w, err)
+}
+
+// writeMuteResult responde al resultado de silenciar o dejar de silenciar.
+func (c *UserController) writeMuteResult(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrSelfMute), errors.Is(err, usecases.ErrTooManyMuted):
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrUserNotFound):
+		httputil.WriteError(w, http.StatusNotFound, "Usuario no encontrado")
+	case err != nil:
+		log.Printf("Error actualizando usuarios silenciados: %v", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "Error interno del servidor")
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// newValidator crea el validador usando los nombres JSON de los campos en los mensajes de error.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// ValidationErrorResponse describe los errores de validación por campo.
+type ValidationErrorResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields"`
+}
+
+// validateRequest valida req según sus etiquetas `validate`. Si hay errores responde con 422
+// y retorna false.
+func validateRequest(w http.ResponseWriter, req interface{}) bool {
+	fields, err := validationFields(req)
+	if err != nil {
+		httputil.WriteError(w, http.StatusInternalServerError, "Error validando la solicitud")
+		return false
+	}
+	if len(fields) == 0 {
+		return true
+	}
+
+	httputil.WriteJSON(w, http.StatusUnprocessableEntity, ValidationErrorResponse{
+		Error:  "La solicitud no es válida",
+		Fields: fields,
+	})
+	return false
+}
+
+// validationFields valida req y retorna un mensaje por cada campo inválido, o un mapa vacío si
+// es válido. Solo retorna error si req no puede validarse.
+func validationFields(req interface{}) (map[string]string, error) {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil, nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		fields[fe.Field()] = validationMessage(fe)
+	}
+	return fields, nil
+}
+
+// validationMessage traduce una regla incumplida a un mensaje legible.
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "es obligatorio"
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return "supera el máximo de " + fe.Param() + " elementos"
+		}
+		return "supera el máximo de " + fe.Param() + " caracteres"
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			return "debe tener al menos " + fe.Param() + " elementos"
+		}
+		return "debe tener al menos " + fe.Param() + " caracteres"
+	case "gt":
+		if fe.Type() == reflect.TypeOf(time.Time{}) {
+			return "debe ser una fecha futura"
+		}
+		return "debe ser mayor que " + fe.Param()
+	case "lte":
+		if fe.Type() == reflect.TypeOf(time.Time{}) {
+			return "no puede ser una fecha futura"
+		}
+		return "debe ser menor o igual que " + fe.Param()
+	case "url":
+		return "debe ser una URL válida"
+	case "oneof":
+		return "debe ser uno de: " + fe.Param()
+	default:
+		return "no cumple la regla " + fe.Tag()
+	}
+}
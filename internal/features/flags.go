@@ -0,0 +1,81 @@
+package features
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Nombres de las funcionalidades que pueden activarse o desactivarse sin redesplegar.
+const (
+	Mentions     = "mentions"
+	RelatedPosts = "related_posts"
+	Reposts      = "reposts"
+)
+
+// defaults define el estado de cada funcionalidad cuando la fuente no la configura.
+var defaults = map[string]bool{
+	Mentions:     true,
+	RelatedPosts: true,
+	Reposts:      true,
+}
+
+// Source es el origen de la configuración de las funcionalidades. Lookup retorna ok=false
+// si la funcionalidad no está definida en la fuente.
+type Source interface {
+	Lookup(name string) (enabled bool, ok bool)
+}
+
+// EnvSource lee cada funcionalidad de la variable de entorno FEATURE_<NOMBRE>, por ejemplo
+// FEATURE_RELATED_POSTS=false.
+type EnvSource struct{}
+
+func (EnvSource) Lookup(name string) (bool, bool) {
+	raw := os.Getenv("FEATURE_" + strings.ToUpper(name))
+	if raw == "" {
+		return false, false
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, false
+	}
+	return v, true
+}
+
+// Flags mantiene en memoria el estado de las funcionalidades.
+type Flags struct {
+	mu     sync.RWMutex
+	values map[string]bool
+}
+
+// New carga el estado de todas las funcionalidades conocidas desde source.
+func New(source Source) *Flags {
+	values := make(map[string]bool, len(defaults))
+	for name, def := range defaults {
+		values[name] = def
+		if v, ok := source.Lookup(name); ok {
+			values[name] = v
+		}
+	}
+	return &Flags{values: values}
+}
+
+// IsEnabled indica si la funcionalidad está activa. Las funcionalidades desconocidas están inactivas.
+func (f *Flags) IsEnabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.values[name]
+}
+
+// All retorna una copia del estado de todas las funcionalidades.
+func (f *Flags) All() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	all := make(map[string]bool, len(f.values))
+	for name, v := range f.values {
+		all[name] = v
+	}
+	return all
+}
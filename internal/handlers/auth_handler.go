@@ -1,10 +1,10 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
 	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
 	"github.com/JuanPidarraga/talkus-backend/internal/service"
 )
@@ -35,7 +35,6 @@ func (h *AuthHandler) GetUserProfile(w http.ResponseWriter,r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(userProfile)
+	httputil.WriteJSON(w, http.StatusOK, userProfile)
 
 }
\ No newline at end of file
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+)
+
+// @Summary Consultar las funcionalidades activas
+// @Description Retorna el estado actual (activa/inactiva) de cada funcionalidad configurable. Solo para administradores.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} map[string]bool "Estado de cada funcionalidad"
+// @Router /admin/flags [get]
+func FeatureFlagsHandler(flags *features.Flags) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httputil.WriteJSON(w, http.StatusOK, flags.All())
+	}
+}
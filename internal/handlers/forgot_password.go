@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
 	"github.com/JuanPidarraga/talkus-backend/internal/service"
 )
 
@@ -36,8 +37,7 @@ func ForgotPasswordHandler(authService *service.AuthService) http.HandlerFunc {
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{
+		httputil.WriteJSON(w, http.StatusOK, map[string]string{
 			"message": "Enlace de recuperación enviado correctamente",
 		})
 	}
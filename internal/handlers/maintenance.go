@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
+)
+
+// @Summary Consultar el modo mantenimiento
+// @Description Retorna si el modo mantenimiento está activo y qué peticiones bloquea. Solo para administradores.
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} middleware.MaintenanceState "Estado del modo mantenimiento"
+// @Router /admin/maintenance [get]
+func MaintenanceStatusHandler(m *middleware.Maintenance) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httputil.WriteJSON(w, http.StatusOK, m.State())
+	}
+}
+
+// @Summary Activar o desactivar el modo mantenimiento
+// @Description Cambia en tiempo de ejecución el modo mantenimiento. Con block "writes" se rechazan con 503 las peticiones que modifican datos; con "reads", las lecturas. Las IPs de MAINTENANCE_ALLOWED_IPS nunca se bloquean. Solo para administradores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param state body middleware.MaintenanceState true "Nuevo estado"
+// @Success 200 {object} middleware.MaintenanceState "Estado guardado"
+// @Failure 400 {object} map[string]string "Estado inválido"
+// @Router /admin/maintenance [put]
+func MaintenanceToggleHandler(m *middleware.Maintenance) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := m.State()
+		if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+			return
+		}
+		if err := m.SetState(state); err != nil {
+			httputil.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, state)
+	}
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
 	"github.com/JuanPidarraga/talkus-backend/internal/service"
 )
 
@@ -31,6 +33,7 @@ func NewRegisterHandler(authService *service.AuthService) *RegisterHandler {
 // @Param user body RegisterRequest true "Datos del usuario a registrar"
 // @Success 201 {object} map[string]string "Usuario creado exitosamente"
 // @Failure 400 {object} map[string]string "Solicitud incorrecta: los datos no son válidos"
+// @Failure 409 {object} map[string]string "El correo ya está registrado"
 // @Failure 500 {object} map[string]string "Error interno al registrar el usuario"
 // @Router /public/register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -42,12 +45,15 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userRecord, err := h.authService.RegisterAndSaveUser(r.Context(), req.Username, req.Email, req.Password)
+	if errors.Is(err, service.ErrEmailAlreadyExists) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Responde con el usuario registrado
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(userRecord)
+	httputil.WriteJSON(w, http.StatusOK, userRecord)
 }
@@ -0,0 +1,32 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// WriteJSON serializa payload en un buffer antes de escribir la respuesta. Así un error de
+// serialización se detecta antes de enviar el código de estado y se responde con 500 en
+// lugar de un cuerpo truncado. Retorna el error de escritura, si lo hubo.
+func WriteJSON(w http.ResponseWriter, status int, payload interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(payload); err != nil {
+		log.Printf("Error serializando respuesta: %v", err)
+		http.Error(w, `{"error": "Error formateando datos"}`, http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteError responde con un objeto JSON {"error": msg}.
+func WriteError(w http.ResponseWriter, status int, msg string) error {
+	return WriteJSON(w, status, map[string]string{"error": msg})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/JuanPidarraga/talkus-backend/internal/service"
+	"github.com/JuanPidarraga/talkus-backend/internal/tracing"
 )
 
 type AuthMiddleware struct {
@@ -39,9 +40,24 @@ func (middleware *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		tracing.SetUser(r.Context(), decodedToken.UID)
 		ctx := context.WithValue(r.Context(), AuthUserKey, decodedToken)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 
 	})
 }
+
+// OptionalAuthenticate valida el token si la petición trae cabecera Authorization y lo deja en
+// el contexto; sin cabecera la petición continúa como anónima. Un token inválido se rechaza
+// igual que en Authenticate.
+func (middleware *AuthMiddleware) OptionalAuthenticate(next http.Handler) http.Handler {
+	authenticated := middleware.Authenticate(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// Compress comprime con gzip las respuestas de texto (JSON, XML, HTML...) de al menos minBytes
+// cuando el cliente lo admite en Accept-Encoding. Las respuestas con otros tipos, como imágenes
+// o archivos ya comprimidos, y las que ya traen Content-Encoding se envían sin cambios.
+func Compress(minBytes, level int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead || !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minBytes: minBytes, level: level, status: http.StatusOK}
+			defer gw.Close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc = strings.TrimSpace(enc)
+		if enc == "gzip" || strings.HasPrefix(enc, "gzip;") && !strings.HasSuffix(enc, "q=0") {
+			return true
+		}
+	}
+	return false
+}
+
+// compressible indica si vale la pena comprimir una respuesta con este Content-Type.
+func compressible(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	return strings.HasPrefix(ct, "text/") ||
+		strings.Contains(ct, "json") ||
+		strings.Contains(ct, "xml") ||
+		strings.Contains(ct, "javascript")
+}
+
+// gzipResponseWriter retiene los primeros bytes de la respuesta hasta saber si alcanza el
+// tamaño mínimo y recién entonces decide si comprimirla.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minBytes int
+	level    int
+
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if !w.decided {
+		w.status = status
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minBytes {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// decide envía la cabecera y los bytes retenidos, comprimidos si bigEnough y el tipo lo permite.
+func (w *gzipResponseWriter) decide(bigEnough bool) error {
+	w.decided = true
+	h := w.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	eligible := compressible(h.Get("Content-Type")) && h.Get("Content-Encoding") == "" &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified
+	if eligible {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	if eligible && bigEnough {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+		if err != nil {
+			return err
+		}
+		w.gz = gz
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.gz != nil {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// FlushError envía lo que haya retenido o comprimido hasta el momento, para las respuestas que
+// se transmiten de a poco. Si aún no se decidió, se comprime solo si ya se alcanzó minBytes.
+// Es el método que usa http.ResponseController.
+func (w *gzipResponseWriter) FlushError() error {
+	if !w.decided {
+		if err := w.decide(len(w.buf) >= w.minBytes); err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		if err := w.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Flush implementa http.Flusher.
+func (w *gzipResponseWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// Unwrap permite a http.ResponseController llegar al ResponseWriter original.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close envía lo que quede retenido y cierra el compresor.
+func (w *gzipResponseWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+)
+
+// MaintenanceBlock indica qué peticiones se rechazan mientras el mantenimiento está activo.
+type MaintenanceBlock string
+
+const (
+	// BlockWrites rechaza las peticiones que modifican datos y mantiene las lecturas.
+	BlockWrites MaintenanceBlock = "writes"
+	// BlockReads rechaza las lecturas y mantiene las peticiones que modifican datos.
+	BlockReads MaintenanceBlock = "reads"
+)
+
+// ErrInvalidMaintenanceBlock indica un valor de bloqueo distinto de "writes" o "reads".
+var ErrInvalidMaintenanceBlock = errors.New("el bloqueo debe ser 'writes' o 'reads'")
+
+// MaintenanceState es el estado del modo mantenimiento.
+type MaintenanceState struct {
+	Enabled bool             `json:"enabled"`
+	Block   MaintenanceBlock `json:"block"`
+}
+
+// Maintenance responde 503 a las peticiones bloqueadas mientras el modo mantenimiento está
+// activo. El estado puede cambiarse en tiempo de ejecución y vive solo en memoria.
+type Maintenance struct {
+	mu         sync.RWMutex
+	state      MaintenanceState
+	allowedIPs map[string]bool
+	exempt     map[string]bool
+}
+
+// NewMaintenance crea el middleware con el estado inicial y las IPs que nunca se bloquean.
+// Un bloqueo inválido se reemplaza por BlockWrites.
+func NewMaintenance(state MaintenanceState, allowedIPs []string) *Maintenance {
+	if !state.Block.valid() {
+		log.Printf("⚠️ Bloqueo de mantenimiento inválido (%q), usando %q", state.Block, BlockWrites)
+		state.Block = BlockWrites
+	}
+
+	m := &Maintenance{
+		state:      state,
+		allowedIPs: make(map[string]bool, len(allowedIPs)),
+		exempt:     make(map[string]bool),
+	}
+	for _, ip := range allowedIPs {
+		m.allowedIPs[ip] = true
+	}
+	return m
+}
+
+func (b MaintenanceBlock) valid() bool {
+	return b == BlockWrites || b == BlockReads
+}
+
+// Exempt excluye la ruta indicada del bloqueo, por ejemplo la que desactiva el mantenimiento.
+func (m *Maintenance) Exempt(path string) {
+	m.mu.Lock()
+	m.exempt[path] = true
+	m.mu.Unlock()
+}
+
+// State retorna el estado actual del modo mantenimiento.
+func (m *Maintenance) State() MaintenanceState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// SetState reemplaza el estado del modo mantenimiento.
+func (m *Maintenance) SetState(state MaintenanceState) error {
+	if !state.Block.valid() {
+		return ErrInvalidMaintenanceBlock
+	}
+
+	m.mu.Lock()
+	m.state = state
+	m.mu.Unlock()
+	log.Printf("Modo mantenimiento: activo=%t, bloqueo=%s", state.Enabled, state.Block)
+	return nil
+}
+
+// Middleware rechaza con 503 las peticiones bloqueadas por el estado actual. La IP del
+// cliente se toma de la conexión, no de cabeceras como X-Forwarded-For.
+func (m *Maintenance) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.blocks(r) {
+			httputil.WriteError(w, http.StatusServiceUnavailable,
+				"El servicio está en mantenimiento, intenta de nuevo más tarde")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *Maintenance) blocks(r *http.Request) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.state.Enabled || m.exempt[r.URL.Path] {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if m.allowedIPs[host] {
+		return false
+	}
+
+	read := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+	if m.state.Block == BlockReads {
+		return read
+	}
+	return !read
+}
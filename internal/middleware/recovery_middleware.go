@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/httputil"
+)
+
+// RequestIDHeader es la cabecera con la que se identifica cada petición en los logs. Si el
+// cliente o un proxy ya la envían se conserva su valor.
+const RequestIDHeader = "X-Request-ID"
+
+// Recover evita que un panic en un handler tumbe el servidor: lo registra con la traza y el
+// identificador de la petición y responde 500 en JSON. Si la respuesta ya había empezado, vuelve
+// a entrar en panic con http.ErrAbortHandler para que net/http cierre la conexión y el cliente no
+// tome la respuesta a medias como completa.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		rw := &recoveryResponseWriter{ResponseWriter: w}
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// panic usado a propósito para cortar la respuesta; lo maneja net/http
+				panic(rec)
+			}
+
+			log.Printf("panic en %s %s (petición %s): %v\n%s", r.Method, r.URL.Path, requestID, rec, debug.Stack())
+			if rw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			httputil.WriteJSON(w, http.StatusInternalServerError, map[string]string{
+				"error":     "Error interno del servidor",
+				"requestId": requestID,
+			})
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// recoveryResponseWriter registra si ya se envió la cabecera de la respuesta.
+type recoveryResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *recoveryResponseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recoveryResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap permite a http.ResponseController llegar al ResponseWriter original.
+func (w *recoveryResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
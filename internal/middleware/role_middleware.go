@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"firebase.google.com/go/v4/auth"
+)
+
+// Roles asignados mediante el custom claim "role" de Firebase.
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+// HasRole indica si el token tiene alguno de los roles indicados en su claim "role".
+func HasRole(token *auth.Token, roles ...string) bool {
+	if token == nil {
+		return false
+	}
+	role, _ := token.Claims["role"].(string)
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole permite el acceso solo a usuarios autenticados con alguno de los roles indicados.
+// Debe usarse después de Authenticate.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, _ := r.Context().Value(AuthUserKey).(*auth.Token)
+			if token == nil {
+				http.Error(w, "❌ token not found", http.StatusUnauthorized)
+				return
+			}
+			if !HasRole(token, roles...) {
+				http.Error(w, "❌ insufficient permissions", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
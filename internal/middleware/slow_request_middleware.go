@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"expvar"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// slowRequests cuenta las peticiones lentas por ruta ("GET /public/posts/{id}"). Se publica
+// con expvar junto con el resto de las métricas del proceso.
+var slowRequests = expvar.NewMap("slow_requests")
+
+// SlowRequestThresholds define a partir de qué duración se considera lenta una petición,
+// según el grupo de rutas. Un umbral de 0 desactiva el registro para ese grupo.
+type SlowRequestThresholds struct {
+	// Default aplica a las peticiones que no entran en los demás grupos.
+	Default time.Duration
+	// Uploads aplica a las peticiones multipart/form-data, que suben imágenes a Cloudinary.
+	Uploads time.Duration
+	// Admin aplica a las rutas bajo /admin, como importaciones y exportaciones.
+	Admin time.Duration
+}
+
+// thresholdFor retorna el umbral que corresponde a la petición.
+func (t SlowRequestThresholds) thresholdFor(r *http.Request) time.Duration {
+	switch {
+	case strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"):
+		return t.Uploads
+	case strings.HasPrefix(r.URL.Path, "/admin/"):
+		return t.Admin
+	default:
+		return t.Default
+	}
+}
+
+// SlowRequests registra con nivel WARN, y cuenta en la métrica slow_requests, las peticiones
+// que tardan más que el umbral de su grupo. El log incluye la ruta de mux, sus parámetros y la
+// consulta, para ubicar el handler sin necesidad de trazas. Debe registrarse con router.Use
+// para que la ruta ya esté resuelta.
+func SlowRequests(thresholds SlowRequestThresholds) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			threshold := thresholds.thresholdFor(r)
+			if threshold <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(rw, r)
+			elapsed := time.Since(start)
+			if elapsed < threshold {
+				return
+			}
+
+			route := r.URL.Path
+			if current := mux.CurrentRoute(r); current != nil {
+				if tmpl, err := current.GetPathTemplate(); err == nil {
+					route = tmpl
+				}
+			}
+			slowRequests.Add(r.Method+" "+route, 1)
+			log.Printf("WARN petición lenta: %s %s vars=%v query=%q status=%d duración=%s umbral=%s petición=%s",
+				r.Method, route, mux.Vars(r), r.URL.RawQuery, rw.status, elapsed.Round(time.Millisecond), threshold,
+				w.Header().Get(RequestIDHeader))
+		})
+	}
+}
+
+// statusResponseWriter registra el código de estado de la respuesta.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap permite a http.ResponseController llegar al ResponseWriter original.
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,8 @@
+package models
+
+// ArchiveMonth es la cantidad de publicaciones públicas creadas en un mes, para la vista de archivo.
+type ArchiveMonth struct {
+	Year  int   `firestore:"year"  json:"year"`
+	Month int   `firestore:"month" json:"month"`
+	Count int64 `firestore:"count" json:"count"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Acciones de moderación registradas en el log de auditoría.
+const (
+	AuditActionFlag     = "flag"
+	AuditActionUnflag   = "unflag"
+	AuditActionDelete   = "delete"
+	AuditActionBan      = "ban"
+	AuditActionTransfer = "transfer"
+	AuditActionMerge    = "merge"
+)
+
+// AuditLog registra quién ejecutó una acción de moderación, sobre qué recurso y por qué.
+type AuditLog struct {
+	ID        string    `firestore:"-"          json:"id"`
+	ActorID   string    `firestore:"actor_id"   json:"actorId"`
+	Action    string    `firestore:"action"     json:"action"`
+	TargetID  string    `firestore:"target_id"  json:"targetId"`
+	Reason    string    `firestore:"reason"     json:"reason"`
+	CreatedAt time.Time `firestore:"created_at" json:"createdAt"`
+}
+
+// MarshalJSON serializa las fechas con TimeFormat.
+func (a AuditLog) MarshalJSON() ([]byte, error) {
+	type auditLog AuditLog
+	return json.Marshal(struct {
+		auditLog
+		CreatedAt Timestamp `json:"createdAt"`
+	}{auditLog: auditLog(a), CreatedAt: Timestamp(a.CreatedAt)})
+}
+
+// AuditLogFilter restringe la consulta del log de auditoría. Los campos vacíos no filtran.
+type AuditLogFilter struct {
+	ActorID string
+	Action  string
+	From    time.Time
+	To      time.Time
+	Limit   int
+	Offset  int
+}
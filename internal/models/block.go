@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Block registra que BlockerID bloqueó a BlockedID. El bloqueo oculta las publicaciones de cada
+// uno al otro e impide a BlockedID interactuar con las publicaciones de BlockerID.
+type Block struct {
+	BlockerID string    `firestore:"blocker_id" json:"blockerId"`
+	BlockedID string    `firestore:"blocked_id" json:"blockedId"`
+	CreatedAt time.Time `firestore:"created_at" json:"createdAt"`
+}
+
+// MarshalJSON serializa las fechas con TimeFormat.
+func (b Block) MarshalJSON() ([]byte, error) {
+	type block Block
+	return json.Marshal(struct {
+		block
+		CreatedAt Timestamp `json:"createdAt"`
+	}{block: block(b), CreatedAt: Timestamp(b.CreatedAt)})
+}
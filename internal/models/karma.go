@@ -0,0 +1,15 @@
+package models
+
+// Pesos de la fórmula de karma. Ajustarlos aquí cambia el cálculo en todo el sistema: en la
+// actualización con cada reacción y en el recálculo completo.
+const (
+	karmaLikeWeight    = 1
+	karmaDislikeWeight = 1
+)
+
+// ComputeKarma calcula la reputación de un usuario a partir de las interacciones
+// recibidas en sus publicaciones. Es lineal, así que también calcula cuánto cambia el karma
+// cuando cambian los likes o dislikes.
+func ComputeKarma(likes, dislikes int64) int64 {
+	return likes*karmaLikeWeight - dislikes*karmaDislikeWeight
+}
@@ -0,0 +1,9 @@
+package models
+
+// LinkPreview son los metadatos Open Graph del primer enlace del contenido de una publicación.
+type LinkPreview struct {
+	URL         string `firestore:"url"         json:"url"`
+	Title       string `firestore:"title"       json:"title"`
+	Description string `firestore:"description" json:"description,omitempty"`
+	ImageURL    string `firestore:"image_url"   json:"imageUrl,omitempty"`
+}
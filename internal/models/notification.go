@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipos de notificación soportados.
+const (
+	NotificationMention = "mention"
+)
+
+// Notification representa un aviso para un usuario sobre un evento que le concierne.
+// PayloadRef referencia el recurso que originó el evento (por ejemplo, el ID de la publicación).
+type Notification struct {
+	ID         string    `firestore:"-"           json:"id"`
+	UserID     string    `firestore:"user_id"     json:"userId"`
+	Type       string    `firestore:"type"        json:"type"`
+	PayloadRef string    `firestore:"payload_ref" json:"payloadRef"`
+	Read       bool      `firestore:"read"        json:"read"`
+	CreatedAt  time.Time `firestore:"created_at"  json:"createdAt"`
+}
+
+// MarshalJSON serializa las fechas con TimeFormat.
+func (n Notification) MarshalJSON() ([]byte, error) {
+	type notification Notification
+	return json.Marshal(struct {
+		notification
+		CreatedAt Timestamp `json:"createdAt"`
+	}{notification: notification(n), CreatedAt: Timestamp(n.CreatedAt)})
+}
+
+// NotificationList agrupa las notificaciones de un usuario con su número de no leídas.
+type NotificationList struct {
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int64           `json:"unreadCount"`
+}
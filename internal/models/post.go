@@ -1,18 +1,106 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
+// Niveles de visibilidad de una publicación.
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityPrivate   = "private"
+)
+
+// Tipos de publicación. Las publicaciones guardadas sin tipo se tratan como PostTypeStandard.
+const (
+	PostTypeStandard     = "standard"
+	PostTypeQuestion     = "question"
+	PostTypeAnnouncement = "announcement"
+)
+
+// Post representa una publicación.
+//
+// Slug identifica la publicación en URLs legibles y es único. Type define qué campos son
+// obligatorios al crearla. Las publicaciones con ExpiresAt se eliminan automáticamente al vencer.
+//
+// ImageFallbackURL apunta a la misma imagen en JPEG cuando ImageURL se guardó en WebP; las
+// respuestas traen ambas y el cliente elige cuál mostrar. ImagePending indica que la imagen aún
+// no se pudo subir y se completará más tarde. ImageModeration es el resultado de la moderación
+// asíncrona de la imagen en Cloudinary (approved o rejected), vacío mientras no llegue.
+// ReviewedAt registra cuándo un moderador actuó sobre la publicación reportada.
+//
+// Reactions cuenta las reacciones distintas de like y dislike, que siguen en Likes y Dislikes.
+// ReadingTimeSeconds se calcula al responder y no se guarda. LinkPreview se completa en segundo
+// plano después de crear la publicación, por lo que puede faltar en la respuesta. Author solo
+// se completa cuando se pide con ?include=author.
+//
+// MergedInto y DeletedAt marcan un duplicado fusionado en otra publicación, que ya no se
+// retorna en las lecturas.
 type Post struct {
-	ID        string    `firestore:"-"             json:"id"`          
-	AuthorID  string    `firestore:"author_id"     json:"author_id"`
-	Title     string    `firestore:"title"         json:"title"`
-	Content   string    `firestore:"content"       json:"content"`
-	CreatedAt time.Time `firestore:"created_at"    json:"created_at"`
-	UpdatedAt time.Time `firestore:"updated_at"    json:"updated_at"`
-	Tags      []string  `firestore:"tags"          json:"tags"`
-	IsFlagged bool     `firestore:"is_flagged"    json:"is_flagged"`
-	ForumID   string    `firestore:"forum_id"      json:"forum_id"`
-	ImageURL  string    `firestore:"image_url"     json:"image_url"`
-	Likes     int       `firestore:"likes"         json:"likes"`
-	Dislikes  int       `firestore:"dislikes"      json:"dislikes"`
-}
\ No newline at end of file
+	ID                 string         `firestore:"-"                  json:"id"`
+	AuthorID           string         `firestore:"author_id"          json:"authorId"`
+	Slug               string         `firestore:"slug"               json:"slug,omitempty"`
+	Type               string         `firestore:"type"               json:"type"`
+	Title              string         `firestore:"title"              json:"title"`
+	Content            string         `firestore:"content"            json:"content"`
+	CreatedAt          time.Time      `firestore:"created_at"         json:"createdAt"`
+	UpdatedAt          time.Time      `firestore:"updated_at"         json:"updatedAt"`
+	Tags               []string       `firestore:"tags"               json:"tags"`
+	ReviewedAt         *time.Time     `firestore:"reviewed_at"        json:"reviewedAt,omitempty"`
+	IsFlagged          bool           `firestore:"is_flagged"         json:"isFlagged"`
+	ForumID            string         `firestore:"forum_id"           json:"forumId"`
+	ImageURL           string         `firestore:"image_url"          json:"imageUrl"`
+	ImagePublicID      string         `firestore:"image_public_id"    json:"-"`
+	ImagePending       bool           `firestore:"image_pending"      json:"imagePending,omitempty"`
+	ImageFallbackURL   string         `firestore:"image_fallback_url" json:"imageFallbackUrl,omitempty"`
+	ImageModeration    string         `firestore:"image_moderation"   json:"imageModeration,omitempty"`
+	Likes              int            `firestore:"likes"              json:"likes"`
+	Dislikes           int            `firestore:"dislikes"           json:"dislikes"`
+	RepostCount        int            `firestore:"repost_count"       json:"repostCount"`
+	Reactions          map[string]int `firestore:"reactions"          json:"reactions,omitempty"`
+	Views              int            `firestore:"views"              json:"views"`
+	ReadingTimeSeconds int            `firestore:"-"                  json:"readingTimeSeconds"`
+	ExpiresAt          *time.Time     `firestore:"expires_at"         json:"expiresAt,omitempty"`
+	Visibility         string         `firestore:"visibility"         json:"visibility"`
+	LinkPreview        *LinkPreview   `firestore:"link_preview"       json:"linkPreview,omitempty"`
+	Author             *PostAuthor    `firestore:"-"                  json:"author,omitempty"`
+	Mentions           []string       `firestore:"mentions"           json:"mentions"`
+	MergedInto         string         `firestore:"merged_into"        json:"-"`
+	DeletedAt          *time.Time     `firestore:"deleted_at"         json:"-"`
+}
+
+// PostFilter restringe el listado de publicaciones. Los campos nil no filtran.
+type PostFilter struct {
+	// HasImage deja solo las publicaciones con imagen (true) o solo las que no tienen (false).
+	HasImage *bool
+	// MutedTags omite las publicaciones con alguna de estas etiquetas.
+	MutedTags []string
+	// MutedAuthorIDs omite las publicaciones de estos autores.
+	MutedAuthorIDs []string
+}
+
+// PostAuthor son los datos mínimos del autor que se pueden incluir en una publicación.
+type PostAuthor struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	PhotoURL    string `json:"photoUrl,omitempty"`
+}
+
+// MarshalJSON serializa las fechas con TimeFormat.
+func (p Post) MarshalJSON() ([]byte, error) {
+	type post Post
+	return json.Marshal(struct {
+		post
+		CreatedAt  Timestamp  `json:"createdAt"`
+		UpdatedAt  Timestamp  `json:"updatedAt"`
+		ReviewedAt *Timestamp `json:"reviewedAt,omitempty"`
+		ExpiresAt  *Timestamp `json:"expiresAt,omitempty"`
+	}{
+		post:       post(p),
+		CreatedAt:  Timestamp(p.CreatedAt),
+		UpdatedAt:  Timestamp(p.UpdatedAt),
+		ReviewedAt: optionalTimestamp(p.ReviewedAt),
+		ExpiresAt:  optionalTimestamp(p.ExpiresAt),
+	})
+}
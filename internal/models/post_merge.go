@@ -0,0 +1,17 @@
+package models
+
+// PostMerge resume la fusión de publicaciones duplicadas en una principal. Las reacciones de
+// un usuario que ya había reaccionado a la principal, o a otro duplicado anterior, se descartan
+// en lugar de moverse, para que cada usuario conserve una sola reacción.
+type PostMerge struct {
+	PrimaryID        string   `json:"primaryId"`
+	MergedIDs        []string `json:"mergedIds"`
+	ReactionsMoved   int      `json:"reactionsMoved"`
+	ReactionsDropped int      `json:"reactionsDropped"`
+	RepostsMoved     int      `json:"repostsMoved"`
+}
+
+// Merged indica si la publicación es un duplicado fusionado en otra.
+func (p *Post) Merged() bool {
+	return p.MergedInto != ""
+}
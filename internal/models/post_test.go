@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPostMarshalsTimesInUTCWithMilliseconds(t *testing.T) {
+	// 08:04:05.120456789 en UTC-5 es 13:04:05.120 en UTC; los microsegundos se descartan
+	bogota := time.FixedZone("UTC-5", -5*60*60)
+	created := time.Date(2024, 5, 1, 8, 4, 5, 120456789, bogota)
+	expires := created.Add(time.Hour)
+	p := Post{ID: "post-1", CreatedAt: created, UpdatedAt: created, ExpiresAt: &expires}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	for field, want := range map[string]string{
+		"createdAt": "2024-05-01T13:04:05.120Z",
+		"updatedAt": "2024-05-01T13:04:05.120Z",
+		"expiresAt": "2024-05-01T14:04:05.120Z",
+	} {
+		if got[field] != want {
+			t.Errorf("%s = %v, se esperaba %q", field, got[field], want)
+		}
+	}
+	if _, ok := got["reviewedAt"]; ok {
+		t.Errorf("reviewedAt vacío no debería serializarse: %v", got["reviewedAt"])
+	}
+}
+
+func TestPostJSONFieldNamesAreCamelCase(t *testing.T) {
+	now := time.Now()
+	p := Post{
+		ID: "post-1", AuthorID: "u1", Slug: "hola", Type: PostTypeStandard, Title: "Hola", Content: "mundo",
+		CreatedAt: now, UpdatedAt: now, Tags: []string{"go"}, ReviewedAt: &now, IsFlagged: true, ForumID: "f1",
+		ImageURL: "https://img.test/a.webp", ImagePublicID: "posts_images/a", ImagePending: true,
+		ImageFallbackURL: "https://img.test/a.jpg", ImageModeration: "approved", Likes: 1, Dislikes: 1,
+		RepostCount: 1, Reactions: map[string]int{"love": 1}, Views: 1, ReadingTimeSeconds: 1, ExpiresAt: &now,
+		Visibility: VisibilityPublic, Mentions: []string{"u2"}, MergedInto: "post-2", DeletedAt: &now,
+		LinkPreview: &LinkPreview{URL: "https://a.test", Title: "A", Description: "d", ImageURL: "https://a.test/i.png"},
+		Author:      &PostAuthor{ID: "u1", DisplayName: "Ana", PhotoURL: "https://a.test/ana.png"},
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"author", "authorId", "content", "createdAt", "dislikes", "expiresAt", "forumId", "id",
+		"imageFallbackUrl", "imageModeration", "imagePending", "imageUrl", "isFlagged", "likes",
+		"linkPreview", "mentions", "reactions", "readingTimeSeconds", "repostCount", "reviewedAt",
+		"slug", "tags", "title", "type", "updatedAt", "views", "visibility",
+	}
+	if keys := sortedKeys(got); !slices.Equal(keys, want) {
+		t.Fatalf("campos de Post = %v\nse esperaban %v", keys, want)
+	}
+
+	nested := map[string][]string{
+		"linkPreview": {"description", "imageUrl", "title", "url"},
+		"author":      {"displayName", "id", "photoUrl"},
+	}
+	for field, want := range nested {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(got[field], &obj); err != nil {
+			t.Fatal(err)
+		}
+		if keys := sortedKeys(obj); !slices.Equal(keys, want) {
+			t.Errorf("campos de %s = %v, se esperaban %v", field, keys, want)
+		}
+	}
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Reacciones que un usuario puede dejar en una publicación. Like y dislike se siguen guardando
+// en los contadores likes y dislikes de la publicación; las demás, en su mapa reactions.
+const (
+	ReactionLike    = "like"
+	ReactionDislike = "dislike"
+	ReactionLove    = "love"
+	ReactionLaugh   = "laugh"
+	ReactionAngry   = "angry"
+	ReactionSad     = "sad"
+)
+
+// PostReaction es la reacción de un usuario a una publicación. Cada usuario tiene como máximo
+// una por publicación.
+type PostReaction struct {
+	PostID    string    `firestore:"post_id"    json:"postId"`
+	UserID    string    `firestore:"user_id"    json:"userId"`
+	Type      string    `firestore:"type"       json:"type"`
+	CreatedAt time.Time `firestore:"created_at" json:"createdAt"`
+}
+
+// MarshalJSON serializa las fechas con TimeFormat.
+func (r PostReaction) MarshalJSON() ([]byte, error) {
+	type postReaction PostReaction
+	return json.Marshal(struct {
+		postReaction
+		CreatedAt Timestamp `json:"createdAt"`
+	}{postReaction: postReaction(r), CreatedAt: Timestamp(r.CreatedAt)})
+}
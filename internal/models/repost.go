@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Repost representa una publicación compartida por un usuario, con un comentario opcional.
+type Repost struct {
+	ID             string    `firestore:"-"                json:"id"`
+	UserID         string    `firestore:"user_id"          json:"userId"`
+	OriginalPostID string    `firestore:"original_post_id" json:"originalPostId"`
+	Comment        string    `firestore:"comment"          json:"comment"`
+	CreatedAt      time.Time `firestore:"created_at"       json:"createdAt"`
+	OriginalPost   *Post     `firestore:"-"                json:"originalPost,omitempty"`
+}
+
+// MarshalJSON serializa las fechas con TimeFormat.
+func (r Repost) MarshalJSON() ([]byte, error) {
+	type repost Repost
+	return json.Marshal(struct {
+		repost
+		CreatedAt Timestamp `json:"createdAt"`
+	}{repost: repost(r), CreatedAt: Timestamp(r.CreatedAt)})
+}
@@ -0,0 +1,7 @@
+package models
+
+// TagCount es una etiqueta junto con cuántas publicaciones la usan.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
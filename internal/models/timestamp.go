@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// TimeFormat es el formato con el que se serializan todas las fechas en JSON: RFC 3339 en UTC
+// con milisegundos fijos, por ejemplo "2024-05-01T13:04:05.120Z". El formato por defecto de
+// time.Time (RFC 3339 con nanosegundos variables) cambia de largo según el valor.
+const TimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Timestamp es un time.Time que se serializa en JSON con TimeFormat. Los modelos guardados en
+// Firestore conservan sus campos como time.Time y lo usan desde su MarshalJSON.
+type Timestamp time.Time
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Time(t).UTC().Format(TimeFormat) + `"`), nil
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var parsed time.Time
+	if err := parsed.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*t = Timestamp(parsed)
+	return nil
+}
+
+// optionalTimestamp convierte una fecha opcional, conservando nil.
+func optionalTimestamp(t *time.Time) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := Timestamp(*t)
+	return &ts
+}
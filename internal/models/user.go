@@ -6,3 +6,25 @@ type User struct {
 	Email string `json:"email"`
 	Password string `json:"password"`
 }
+
+// UserProfile agrupa los datos públicos de un usuario junto con sus estadísticas.
+type UserProfile struct {
+	UID            string    `json:"uid"`
+	Username       string    `json:"username"`
+	PostCount      int64     `json:"postCount"`
+	LikesReceived  int64     `json:"likesReceived"`
+	Karma          int64     `json:"karma"`
+	FollowersCount int64     `json:"followersCount"`
+	FollowingCount int64     `json:"followingCount"`
+	JoinedAt       Timestamp `json:"joinedAt" swaggertype:"string" format:"date-time"`
+}
+
+// PublicUser son los datos de un usuario que se pueden mostrar a cualquiera, sin su correo
+// ni otros campos privados.
+type PublicUser struct {
+	UID      string    `json:"uid"`
+	Username string    `json:"username"`
+	PhotoURL string    `json:"photoUrl,omitempty"`
+	Karma    int64     `json:"karma"`
+	JoinedAt Timestamp `json:"joinedAt" swaggertype:"string" format:"date-time"`
+}
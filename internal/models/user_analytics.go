@@ -0,0 +1,32 @@
+package models
+
+// PostEngagement son las interacciones que recibió una publicación.
+type PostEngagement struct {
+	PostID      string         `json:"postId"`
+	Title       string         `json:"title"`
+	Slug        string         `json:"slug,omitempty"`
+	CreatedAt   Timestamp      `json:"createdAt" swaggertype:"string" format:"date-time"`
+	Views       int            `json:"views"`
+	Likes       int            `json:"likes"`
+	Dislikes    int            `json:"dislikes"`
+	RepostCount int            `json:"repostCount"`
+	Reactions   map[string]int `json:"reactions,omitempty"`
+}
+
+// EngagementTotals suma las interacciones de todas las publicaciones del periodo.
+type EngagementTotals struct {
+	Posts    int `json:"posts"`
+	Views    int `json:"views"`
+	Likes    int `json:"likes"`
+	Dislikes int `json:"dislikes"`
+	Reposts  int `json:"reposts"`
+}
+
+// UserAnalytics resume las interacciones con las publicaciones de un autor. Totals cubre todas
+// las publicaciones del periodo y Posts solo la página pedida, de la más reciente a la más
+// antigua.
+type UserAnalytics struct {
+	UserID string           `json:"userId"`
+	Totals EngagementTotals `json:"totals"`
+	Posts  []PostEngagement `json:"posts"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// UserPreferences son las preferencias del usuario para su listado de publicaciones. Se guardan
+// en el campo preferences de su documento; el valor cero no filtra nada.
+type UserPreferences struct {
+	// MutedTags oculta las publicaciones con alguna de estas etiquetas.
+	MutedTags []string `firestore:"muted_tags" json:"mutedTags"`
+	// MutedUsers oculta las publicaciones de estos autores.
+	MutedUsers []string `firestore:"muted_users" json:"mutedUsers"`
+	// DefaultSort es el orden usado cuando la petición no indica uno; vacío usa el habitual.
+	DefaultSort string `firestore:"default_sort" json:"defaultSort"`
+}
+
+// IsZero indica si las preferencias no cambian el listado.
+func (p UserPreferences) IsZero() bool {
+	return len(p.MutedTags) == 0 && len(p.MutedUsers) == 0 && p.DefaultSort == ""
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"google.golang.org/api/iterator"
+)
+
+// AuditLogRepository se encarga de interactuar con la colección "audit_logs" en Firestore.
+type AuditLogRepository struct {
+	db *firestore.Client
+}
+
+// NewAuditLogRepository crea una nueva instancia del repositorio.
+func NewAuditLogRepository(db *firestore.Client) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create guarda la entrada y le asigna su ID.
+func (r *AuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	doc, _, err := r.db.Collection("audit_logs").Add(ctx, entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = doc.ID
+	return nil
+}
+
+// List retorna las entradas que cumplen el filtro, de la más reciente a la más antigua.
+func (r *AuditLogRepository) List(ctx context.Context, f models.AuditLogFilter) ([]*models.AuditLog, error) {
+	iter := r.filtered(f).
+		OrderBy("created_at", firestore.Desc).
+		Offset(f.Offset).
+		Limit(f.Limit).
+		Documents(ctx)
+	defer iter.Stop()
+
+	entries := make([]*models.AuditLog, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating audit logs: %w", err)
+		}
+
+		var entry models.AuditLog
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("error decoding audit log: %w", err)
+		}
+		entry.ID = doc.Ref.ID
+
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// Count retorna cuántas entradas cumplen el filtro, sin considerar Limit ni Offset.
+func (r *AuditLogRepository) Count(ctx context.Context, f models.AuditLogFilter) (int64, error) {
+	q := r.filtered(f)
+	res, err := q.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting audit logs: %w", err)
+	}
+	return aggregationInt(res, "total"), nil
+}
+
+// filtered aplica a la colección los filtros de f, excepto la paginación.
+func (r *AuditLogRepository) filtered(f models.AuditLogFilter) firestore.Query {
+	q := r.db.Collection("audit_logs").Query
+	if f.ActorID != "" {
+		q = q.Where("actor_id", "==", f.ActorID)
+	}
+	if f.Action != "" {
+		q = q.Where("action", "==", f.Action)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at", ">=", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at", "<", f.To)
+	}
+	return q
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// BlockRepository se encarga de interactuar con la colección "blocks" en Firestore.
+type BlockRepository struct {
+	db *firestore.Client
+}
+
+// NewBlockRepository crea una nueva instancia del repositorio.
+func NewBlockRepository(db *firestore.Client) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// blockRef retorna el documento del bloqueo; un documento por par garantiza que no se repita.
+func (r *BlockRepository) blockRef(blockerID, blockedID string) *firestore.DocumentRef {
+	return r.db.Collection("blocks").Doc(blockerID + "_" + blockedID)
+}
+
+// Create guarda el bloqueo, reemplazando el anterior del mismo par si existía.
+func (r *BlockRepository) Create(ctx context.Context, b *models.Block) error {
+	_, err := r.blockRef(b.BlockerID, b.BlockedID).Set(ctx, b)
+	return err
+}
+
+// Delete elimina el bloqueo de blockerID a blockedID. No falla si no existía.
+func (r *BlockRepository) Delete(ctx context.Context, blockerID, blockedID string) error {
+	_, err := r.blockRef(blockerID, blockedID).Delete(ctx)
+	return err
+}
+
+// GetRelated retorna los IDs de los usuarios que userID bloqueó y de los que lo bloquearon,
+// sin repetir.
+func (r *BlockRepository) GetRelated(ctx context.Context, userID string) ([]string, error) {
+	blocks := r.db.Collection("blocks")
+	byUser, err := blocks.Where("blocker_id", "==", userID).Select("blocked_id").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+	ofUser, err := blocks.Where("blocked_id", "==", userID).Select("blocker_id").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing blocks: %w", err)
+	}
+
+	seen := make(map[string]bool, len(byUser)+len(ofUser))
+	ids := make([]string, 0, len(byUser)+len(ofUser))
+	add := func(docs []*firestore.DocumentSnapshot, field string) {
+		for _, doc := range docs {
+			if id, _ := doc.Data()[field].(string); id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	add(byUser, "blocked_id")
+	add(ofUser, "blocker_id")
+	return ids, nil
+}
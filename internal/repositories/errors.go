@@ -0,0 +1,13 @@
+package repositories
+
+import "errors"
+
+// ErrNotFound se retorna cuando el documento solicitado no existe en Firestore.
+var ErrNotFound = errors.New("documento no encontrado")
+
+// ErrAlreadyExists se retorna cuando se intenta crear un documento que ya existe.
+var ErrAlreadyExists = errors.New("el documento ya existe")
+
+// ErrTooManyWrites se retorna cuando una operación necesita más escrituras de las que admite una
+// transacción de Firestore.
+var ErrTooManyWrites = errors.New("la operación supera el máximo de escrituras de una transacción")
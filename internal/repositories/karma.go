@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// El karma guardado en users.karma es la única fuente: se ajusta en la misma transacción que
+// cambia los likes o dislikes de una publicación, o que la mueve de autor, y el recálculo
+// completo de los administradores lo reconstruye con la misma fórmula.
+
+// karmaDeltas acumula por autor cuánto cambia su karma en una escritura.
+type karmaDeltas map[string]int64
+
+// add suma al autor el karma de likes y dislikes, que pueden ser negativos. Las publicaciones
+// anónimas no tienen a quién sumarlo.
+func (k karmaDeltas) add(authorID string, likes, dislikes int) {
+	if authorID != "" {
+		k[authorID] += models.ComputeKarma(int64(likes), int64(dislikes))
+	}
+}
+
+// existingAuthors lee en la transacción los usuarios de k y retorna sus referencias, omitiendo
+// los que ya no existen. Como toda lectura, debe hacerse antes de la primera escritura.
+func (k karmaDeltas) existingAuthors(db *firestore.Client, tx *firestore.Transaction) ([]*firestore.DocumentRef, error) {
+	refs := make([]*firestore.DocumentRef, 0, len(k))
+	for id, delta := range k {
+		if delta != 0 {
+			refs = append(refs, db.Collection("users").Doc(id))
+		}
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	docs, err := tx.GetAll(refs)
+	if err != nil {
+		return nil, err
+	}
+	existing := refs[:0]
+	for _, doc := range docs {
+		if doc.Exists() {
+			existing = append(existing, doc.Ref)
+		}
+	}
+	return existing, nil
+}
+
+// apply escribe en la transacción los cambios de karma de los usuarios de refs, obtenidos con
+// existingAuthors.
+func (k karmaDeltas) apply(tx *firestore.Transaction, refs []*firestore.DocumentRef) error {
+	for _, ref := range refs {
+		if err := tx.Update(ref, []firestore.Update{{Path: "karma", Value: firestore.Increment(k[ref.ID])}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NotificationRepository se encarga de interactuar con la colección "notifications" en Firestore.
+type NotificationRepository struct {
+	db *firestore.Client
+}
+
+// NewNotificationRepository crea una nueva instancia del repositorio.
+func NewNotificationRepository(db *firestore.Client) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create guarda la notificación y le asigna su ID.
+func (r *NotificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	doc, _, err := r.db.Collection("notifications").Add(ctx, n)
+	if err != nil {
+		return err
+	}
+	n.ID = doc.ID
+	return nil
+}
+
+// GetByUser retorna las notificaciones del usuario de la más reciente a la más antigua.
+func (r *NotificationRepository) GetByUser(ctx context.Context, userID string) ([]*models.Notification, error) {
+	iter := r.db.
+		Collection("notifications").
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	notifications := make([]*models.Notification, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating notifications: %w", err)
+		}
+
+		var n models.Notification
+		if err := doc.DataTo(&n); err != nil {
+			return nil, fmt.Errorf("error decoding notification: %w", err)
+		}
+		n.ID = doc.Ref.ID
+
+		notifications = append(notifications, &n)
+	}
+	return notifications, nil
+}
+
+// CountUnread retorna cuántas notificaciones no leídas tiene el usuario.
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	q := r.db.Collection("notifications").
+		Where("user_id", "==", userID).
+		Where("read", "==", false)
+	res, err := q.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting notifications: %w", err)
+	}
+	return aggregationInt(res, "total"), nil
+}
+
+// MarkRead marca como leída la notificación indicada. Retorna ErrNotFound si no existe
+// o si pertenece a otro usuario.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, notificationID string) error {
+	ref := r.db.Collection("notifications").Doc(notificationID)
+
+	return r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if owner, _ := doc.Data()["user_id"].(string); owner != userID {
+			return ErrNotFound
+		}
+		return tx.Update(ref, []firestore.Update{{Path: "read", Value: true}})
+	})
+}
+
+// MarkAllRead marca como leídas todas las notificaciones no leídas del usuario en un solo envío
+// por lotes y retorna cuántas se actualizaron. Las que fallan simplemente siguen sin leer.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	docs, err := r.db.Collection("notifications").
+		Where("user_id", "==", userID).
+		Where("read", "==", false).
+		Select().
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("error listing unread notifications: %w", err)
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	bw := r.db.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(docs))
+	for _, doc := range docs {
+		job, err := bw.Update(doc.Ref, []firestore.Update{{Path: "read", Value: true}})
+		if err != nil {
+			bw.End()
+			return 0, fmt.Errorf("error queuing notification update: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+
+	updated := 0
+	for _, job := range jobs {
+		if _, err := job.Results(); err == nil {
+			updated++
+		}
+	}
+	return updated, nil
+}
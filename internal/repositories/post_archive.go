@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// El archivo se lee de la colección "archive_months", con un documento por mes (UTC) que cuenta
+// sus publicaciones públicas. Create, CreateMany, Delete y Merge lo mantienen al día, así que
+// leerlo cuesta lo mismo sin importar la antigüedad del sitio. Las publicaciones guardadas antes
+// de que existieran los contadores se cuentan una sola vez con rebuildArchive.
+
+// archiveMonthRef es el contador del mes (UTC) en que se creó una publicación.
+func archiveMonthRef(db *firestore.Client, createdAt time.Time) *firestore.DocumentRef {
+	return db.Collection("archive_months").Doc(createdAt.UTC().Format("2006-01"))
+}
+
+// countsInArchive indica si una publicación con esa visibilidad entra en el archivo. Las
+// publicaciones antiguas guardadas sin visibilidad son públicas.
+func countsInArchive(visibility string) bool {
+	return visibility != models.VisibilityFollowers && visibility != models.VisibilityPrivate
+}
+
+// archiveDelta es la escritura que suma n al contador del mes de createdAt.
+func archiveDelta(createdAt time.Time, n int) map[string]interface{} {
+	created := createdAt.UTC()
+	return map[string]interface{}{
+		"year":  created.Year(),
+		"month": int(created.Month()),
+		"count": firestore.Increment(n),
+	}
+}
+
+// CountPublicByMonth retorna cuántas publicaciones públicas se crearon en cada mes (UTC), del
+// más reciente al más antiguo, omitiendo los meses sin publicaciones. Lee los contadores por
+// mes; la primera vez los reconstruye a partir de las publicaciones con rebuildArchive.
+func (r *PostRepository) CountPublicByMonth(ctx context.Context) ([]models.ArchiveMonth, error) {
+	meta, err := postsMetaRef(r.db).Get(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, fmt.Errorf("error getting posts metadata: %w", err)
+	}
+	if err != nil || meta.Data()["archive_built_at"] == nil {
+		if err := r.rebuildArchive(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	iter := r.db.Collection("archive_months").Where("count", ">", 0).Documents(ctx)
+	defer iter.Stop()
+
+	months := make([]models.ArchiveMonth, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing archive months: %w", err)
+		}
+		var m models.ArchiveMonth
+		if err := doc.DataTo(&m); err != nil {
+			return nil, fmt.Errorf("error decoding archive month: %w", err)
+		}
+		months = append(months, m)
+	}
+	sort.Slice(months, func(i, j int) bool {
+		if months[i].Year != months[j].Year {
+			return months[i].Year > months[j].Year
+		}
+		return months[i].Month > months[j].Month
+	})
+	return months, nil
+}
+
+// rebuildArchive recalcula los contadores por mes desde las publicaciones y marca el archivo
+// como construido en "meta/posts". Cada mes se resuelve con dos agregaciones: el total y las no
+// públicas, que se restan para contar también las publicaciones antiguas guardadas sin
+// visibilidad; de cada mes se descuentan los duplicados públicos fusionados en otra publicación.
+// Las publicaciones que se creen o eliminen mientras corre pueden quedar mal contadas.
+func (r *PostRepository) rebuildArchive(ctx context.Context) error {
+	counts := make(map[time.Time]int64)
+	newest, err := r.firstCreatedAt(ctx, firestore.Desc)
+	if err != nil {
+		return err
+	}
+	if !newest.IsZero() {
+		oldest, err := r.firstCreatedAt(ctx, firestore.Asc)
+		if err != nil {
+			return err
+		}
+		merged, err := r.countMergedPublicByMonth(ctx)
+		if err != nil {
+			return err
+		}
+
+		posts := r.db.Collection("posts")
+		first := time.Date(oldest.Year(), oldest.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for start := time.Date(newest.Year(), newest.Month(), 1, 0, 0, 0, 0, time.UTC); !start.Before(first); start = start.AddDate(0, -1, 0) {
+			inMonth := posts.
+				Where("created_at", ">=", start).
+				Where("created_at", "<", start.AddDate(0, 1, 0))
+
+			all, err := inMonth.NewAggregationQuery().WithCount("total").Get(ctx)
+			if err != nil {
+				return fmt.Errorf("error counting posts: %w", err)
+			}
+			total := aggregationInt(all, "total")
+			if total == 0 {
+				continue
+			}
+			notPublic := inMonth.Where("visibility", "in", []string{models.VisibilityFollowers, models.VisibilityPrivate})
+			hidden, err := notPublic.NewAggregationQuery().WithCount("total").Get(ctx)
+			if err != nil {
+				return fmt.Errorf("error counting posts: %w", err)
+			}
+			if count := total - aggregationInt(hidden, "total") - merged[start]; count > 0 {
+				counts[start] = count
+			}
+		}
+	}
+
+	// también se reescriben los contadores existentes de meses que ya no tienen publicaciones
+	stale, err := r.db.Collection("archive_months").Documents(ctx).GetAll()
+	if err != nil {
+		return fmt.Errorf("error listing archive months: %w", err)
+	}
+	bw := r.db.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(counts)+len(stale))
+	for _, doc := range stale {
+		var m models.ArchiveMonth
+		if err := doc.DataTo(&m); err != nil {
+			return fmt.Errorf("error decoding archive month: %w", err)
+		}
+		if _, recounted := counts[time.Date(m.Year, time.Month(m.Month), 1, 0, 0, 0, 0, time.UTC)]; recounted {
+			continue
+		}
+		job, err := bw.Set(doc.Ref, map[string]interface{}{"count": 0}, firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	for start, count := range counts {
+		job, err := bw.Set(archiveMonthRef(r.db, start), models.ArchiveMonth{Year: start.Year(), Month: int(start.Month()), Count: count})
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return fmt.Errorf("error saving archive months: %w", err)
+		}
+	}
+
+	_, err = postsMetaRef(r.db).Set(ctx, map[string]interface{}{"archive_built_at": time.Now()}, firestore.MergeAll)
+	return err
+}
+
+// countMergedPublicByMonth retorna cuántos duplicados fusionados públicos se crearon en cada mes
+// (UTC), indexados por el primer instante del mes. Son pocos, así que se leen en una sola consulta.
+func (r *PostRepository) countMergedPublicByMonth(ctx context.Context) (map[time.Time]int64, error) {
+	docs, err := r.db.Collection("posts").
+		Select("created_at", "visibility").
+		Where("merged_into", ">", "").
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing merged posts: %w", err)
+	}
+
+	counts := make(map[time.Time]int64)
+	for _, doc := range docs {
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if !countsInArchive(p.Visibility) {
+			continue
+		}
+		created := p.CreatedAt.UTC()
+		counts[time.Date(created.Year(), created.Month(), 1, 0, 0, 0, 0, time.UTC)]++
+	}
+	return counts, nil
+}
+
+// addToArchive suma al archivo las publicaciones públicas de posts, que ya se guardaron. Se usa
+// tras CreateMany, donde no hay una transacción que agrupe las escrituras.
+func (r *PostRepository) addToArchive(ctx context.Context, posts []*models.Post) error {
+	deltas := make(map[string]int)
+	dates := make(map[string]time.Time)
+	for _, p := range posts {
+		if countsInArchive(p.Visibility) {
+			id := archiveMonthRef(r.db, p.CreatedAt).ID
+			deltas[id]++
+			dates[id] = p.CreatedAt
+		}
+	}
+
+	bw := r.db.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(deltas))
+	for id, n := range deltas {
+		job, err := bw.Set(archiveMonthRef(r.db, dates[id]), archiveDelta(dates[id], n), firestore.MergeAll)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return fmt.Errorf("error updating archive months: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+func TestArchiveCountersFollowWrites(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPostRepository(newEmulatorClient(t))
+
+	// un mes sin otras publicaciones en el emulador
+	month := time.Date(1990, time.March, 10, 12, 0, 0, 0, time.UTC)
+	countFor := func() int64 {
+		t.Helper()
+		months, err := repo.CountPublicByMonth(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range months {
+			if m.Year == month.Year() && time.Month(m.Month) == month.Month() {
+				return m.Count
+			}
+		}
+		return 0
+	}
+	before := countFor()
+
+	imported := []*models.Post{
+		{Title: "importada", CreatedAt: month},
+		{Title: "importada privada", CreatedAt: month, Visibility: models.VisibilityPrivate},
+	}
+	for i, err := range repo.CreateMany(ctx, imported) {
+		if err != nil {
+			t.Fatalf("CreateMany[%d]: %v", i, err)
+		}
+	}
+	primary := &models.Post{Title: "principal", CreatedAt: month, Visibility: models.VisibilityPublic}
+	duplicate := &models.Post{Title: "duplicada", CreatedAt: month.Add(time.Hour)}
+	for _, p := range []*models.Post{primary, duplicate} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range append(imported, primary, duplicate) {
+		t.Cleanup(func() { repo.Delete(ctx, p.ID) })
+	}
+	if got := countFor(); got != before+3 {
+		t.Fatalf("tras crear: %d publicaciones en el mes, se esperaban %d", got, before+3)
+	}
+
+	if _, _, err := repo.Merge(ctx, primary.ID, []string{duplicate.ID}); err != nil {
+		t.Fatal(err)
+	}
+	// eliminar el duplicado ya fusionado no lo descuenta dos veces
+	for _, id := range []string{imported[0].ID, imported[1].ID, duplicate.ID} {
+		if err := repo.Delete(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := countFor(); got != before+1 {
+		t.Fatalf("tras fusionar y eliminar: %d publicaciones en el mes, se esperaban %d", got, before+1)
+	}
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// maxTransactionWrites es el máximo de escrituras que Firestore admite en una transacción.
+const maxTransactionWrites = 500
+
+// Merge fusiona las publicaciones duplicateIDs en primaryID en una sola transacción: mueve a la
+// principal las reacciones y los reposts de los duplicados y le suma sus contadores (reacciones,
+// reposts y vistas), y pasa el karma de esos likes y dislikes a su autor. Los duplicados no se
+// eliminan: quedan marcados con merged_into y deleted_at, con sus contadores en cero, se
+// descuentan del archivo y sus slugs pasan a apuntar a la principal. Un usuario con reacción en
+// la principal o en un duplicado anterior conserva esa y la otra se descarta, descontándola de
+// los contadores. Retorna el resumen y los duplicados fusionados, ErrNotFound si alguna
+// publicación no existe o ya fue fusionada, o ErrTooManyWrites si la fusión no cabe en una
+// transacción.
+func (r *PostRepository) Merge(ctx context.Context, primaryID string, duplicateIDs []string) (*models.PostMerge, []*models.Post, error) {
+	var merge *models.PostMerge
+	var merged []*models.Post
+
+	err := r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		merge = &models.PostMerge{PrimaryID: primaryID, MergedIDs: duplicateIDs}
+		merged = make([]*models.Post, 0, len(duplicateIDs))
+
+		primaryRef := r.db.Collection("posts").Doc(primaryID)
+		refs := []*firestore.DocumentRef{primaryRef}
+		for _, id := range duplicateIDs {
+			refs = append(refs, r.db.Collection("posts").Doc(id))
+		}
+		docs, err := tx.GetAll(refs)
+		if err != nil {
+			return err
+		}
+		for i, doc := range docs {
+			if !doc.Exists() {
+				return fmt.Errorf("%w: %s", ErrNotFound, doc.Ref.ID)
+			}
+			var p models.Post
+			if err := doc.DataTo(&p); err != nil {
+				return err
+			}
+			if p.Merged() {
+				return fmt.Errorf("%w: %s", ErrNotFound, doc.Ref.ID)
+			}
+			if i > 0 {
+				p.ID = doc.Ref.ID
+				merged = append(merged, &p)
+			}
+		}
+
+		reactionDocs, err := tx.Documents(r.db.Collection("post_reactions").Where("post_id", "in", duplicateIDs)).GetAll()
+		if err != nil {
+			return fmt.Errorf("error listing reactions: %w", err)
+		}
+		repostDocs, err := tx.Documents(r.db.Collection("reposts").Where("original_post_id", "in", duplicateIDs)).GetAll()
+		if err != nil {
+			return fmt.Errorf("error listing reposts: %w", err)
+		}
+
+		// reacciones de la principal de los usuarios que reaccionaron a algún duplicado
+		reactions := make(map[string][]models.PostReaction, len(duplicateIDs))
+		var primaryReactionRefs []*firestore.DocumentRef
+		seen := make(map[string]bool)
+		for _, doc := range reactionDocs {
+			var reaction models.PostReaction
+			if err := doc.DataTo(&reaction); err != nil {
+				return err
+			}
+			reactions[reaction.PostID] = append(reactions[reaction.PostID], reaction)
+			if !seen[reaction.UserID] {
+				seen[reaction.UserID] = true
+				primaryReactionRefs = append(primaryReactionRefs, r.db.Collection("post_reactions").Doc(primaryID+"_"+reaction.UserID))
+			}
+		}
+		reacted := make(map[string]bool, len(primaryReactionRefs))
+		if len(primaryReactionRefs) > 0 {
+			primaryReactions, err := tx.GetAll(primaryReactionRefs)
+			if err != nil {
+				return err
+			}
+			for _, doc := range primaryReactions {
+				if doc.Exists() {
+					userID, _ := doc.Data()["user_id"].(string)
+					reacted[userID] = true
+				}
+			}
+		}
+
+		// se recorren en el orden de duplicateIDs para que, entre duplicados, gane el primero
+		dropped := make(map[string]bool)
+		for _, p := range merged {
+			for _, reaction := range reactions[p.ID] {
+				if reacted[reaction.UserID] {
+					dropped[p.ID+"_"+reaction.UserID] = true
+					continue
+				}
+				reacted[reaction.UserID] = true
+			}
+		}
+
+		// el karma de los duplicados pasa al autor de la principal, sin las reacciones descartadas
+		var primary models.Post
+		if err := docs[0].DataTo(&primary); err != nil {
+			return err
+		}
+		karma := karmaDeltas{}
+		for _, p := range merged {
+			karma.add(p.AuthorID, -p.Likes, -p.Dislikes)
+			karma.add(primary.AuthorID, p.Likes, p.Dislikes)
+			for _, reaction := range reactions[p.ID] {
+				if dropped[p.ID+"_"+reaction.UserID] {
+					switch reaction.Type {
+					case models.ReactionLike:
+						karma.add(primary.AuthorID, -1, 0)
+					case models.ReactionDislike:
+						karma.add(primary.AuthorID, 0, -1)
+					}
+				}
+			}
+		}
+		authors, err := karma.existingAuthors(r.db, tx)
+		if err != nil {
+			return err
+		}
+
+		writes := 2*len(reactionDocs) + len(repostDocs) + 3*len(merged) + len(authors) + 2
+		if writes > maxTransactionWrites {
+			return ErrTooManyWrites
+		}
+
+		now := time.Now()
+		deltas := make(map[string]int)
+		for _, p := range merged {
+			for reaction, n := range reactionTallies(p) {
+				deltas[reactionField(reaction)] += n
+			}
+			deltas["repost_count"] += p.RepostCount
+			deltas["views"] += p.Views
+
+			for _, reaction := range reactions[p.ID] {
+				if err := tx.Delete(r.db.Collection("post_reactions").Doc(p.ID + "_" + reaction.UserID)); err != nil {
+					return err
+				}
+				if dropped[p.ID+"_"+reaction.UserID] {
+					deltas[reactionField(reaction.Type)]--
+					merge.ReactionsDropped++
+					continue
+				}
+				reaction.PostID = primaryID
+				if err := tx.Set(r.db.Collection("post_reactions").Doc(primaryID+"_"+reaction.UserID), reaction); err != nil {
+					return err
+				}
+				merge.ReactionsMoved++
+			}
+
+			// los contadores ya se sumaron a la principal; se quitan los campos por los que el
+			// duplicado aparecería en las consultas de aleatorias, sugerencias y expiración
+			if err := tx.Update(r.db.Collection("posts").Doc(p.ID), []firestore.Update{
+				{Path: "merged_into", Value: primaryID},
+				{Path: "deleted_at", Value: now},
+				{Path: "updated_at", Value: now},
+				{Path: "likes", Value: 0},
+				{Path: "dislikes", Value: 0},
+				{Path: "reactions", Value: firestore.Delete},
+				{Path: "repost_count", Value: 0},
+				{Path: "views", Value: 0},
+				{Path: "random", Value: firestore.Delete},
+				{Path: "title_lower", Value: firestore.Delete},
+				{Path: "expires_at", Value: firestore.Delete},
+			}); err != nil {
+				return err
+			}
+			if countsInArchive(p.Visibility) {
+				if err := tx.Set(archiveMonthRef(r.db, p.CreatedAt), archiveDelta(p.CreatedAt, -1), firestore.MergeAll); err != nil {
+					return err
+				}
+			}
+			// las URLs del duplicado llevan a la principal
+			if p.Slug != "" {
+				if err := tx.Set(r.db.Collection("slugs").Doc(p.Slug), map[string]interface{}{"post_id": primaryID}); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, doc := range repostDocs {
+			if err := tx.Update(doc.Ref, []firestore.Update{{Path: "original_post_id", Value: primaryID}}); err != nil {
+				return err
+			}
+			merge.RepostsMoved++
+		}
+
+		updates := []firestore.Update{{Path: "updated_at", Value: now}}
+		for field, n := range deltas {
+			if n != 0 {
+				updates = append(updates, firestore.Update{Path: field, Value: firestore.Increment(n)})
+			}
+		}
+		if err := tx.Update(primaryRef, updates); err != nil {
+			return err
+		}
+		return tx.Set(postsMetaRef(r.db), map[string]interface{}{"deleted_at": now}, firestore.MergeAll)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return merge, merged, nil
+}
@@ -3,11 +3,19 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"github.com/JuanPidarraga/talkus-backend/internal/models"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type PostRepository struct {
@@ -18,10 +26,216 @@ func NewPostRepository(db *firestore.Client) *PostRepository {
 	return &PostRepository{db: db}
 }
 
-func (r *PostRepository) GetAll(ctx context.Context) ([]*models.Post, error) {
+// GetAll retorna las publicaciones que cumplen f, de la más reciente a la más antigua. Las que
+// comparten created_at se desempatan por ID para que el orden sea estable entre consultas.
+// Las etiquetas y autores silenciados se descartan al leer, ya que Firestore no combina bien
+// varios filtros de exclusión en una consulta.
+func (r *PostRepository) GetAll(ctx context.Context, f models.PostFilter) ([]*models.Post, error) {
+	q := r.db.Collection("posts").Query
+	withImage := f.HasImage != nil && *f.HasImage
+	if f.HasImage != nil {
+		if withImage {
+			// Firestore exige ordenar primero por el campo de una desigualdad; el orden por fecha
+			// se restablece al terminar
+			q = q.Where("image_url", "!=", "").OrderBy("image_url", firestore.Asc)
+		} else {
+			q = q.Where("image_url", "==", "")
+		}
+	}
+	iter := q.
+		OrderBy("created_at", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	posts := make([]*models.Post, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating posts: %w", err)
+		}
+
+		var p models.Post
+
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		if slices.Contains(f.MutedAuthorIDs, p.AuthorID) || slices.ContainsFunc(p.Tags, func(t string) bool {
+			return slices.Contains(f.MutedTags, t)
+		}) {
+			continue
+		}
+
+		posts = append(posts, &p)
+	}
+	if withImage {
+		sort.SliceStable(posts, func(i, j int) bool {
+			if !posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
+				return posts[i].CreatedAt.After(posts[j].CreatedAt)
+			}
+			return posts[i].ID > posts[j].ID
+		})
+	}
+	return posts, nil
+}
+
+// Each recorre todas las publicaciones sin cargarlas en memoria a la vez y llama a fn con cada
+// una, de la más reciente a la más antigua (o al revés si oldestFirst) o, si byViews, de la más
+// vista a la menos vista. Se detiene en el primer error de fn y lo retorna.
+func (r *PostRepository) Each(ctx context.Context, byViews, oldestFirst bool, fn func(*models.Post) error) error {
+	q := r.db.Collection("posts").Query
+	if byViews {
+		q = q.OrderBy("views", firestore.Desc)
+	}
+	dir := firestore.Desc
+	if oldestFirst && !byViews {
+		dir = firestore.Asc
+	}
+	iter := q.
+		OrderBy("created_at", dir).
+		OrderBy(firestore.DocumentID, dir).
+		Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating posts: %w", err)
+		}
+
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+}
+
+// GetRandom retorna una publicación pública y no reportada elegida al azar, o ErrNotFound si
+// no hay ninguna. Cada publicación guarda al crearse un valor aleatorio en "random"; se toma
+// la primera cuyo valor sea mayor o igual a uno nuevo, volviendo al principio si no hay.
+// Las publicaciones creadas antes de agregar el campo no participan.
+func (r *PostRepository) GetRandom(ctx context.Context) (*models.Post, error) {
+	eligible := r.db.Collection("posts").
+		Where("is_flagged", "==", false).
+		Where("visibility", "==", models.VisibilityPublic)
+
+	for _, from := range []float64{rand.Float64(), 0} {
+		docs, err := eligible.
+			Where("random", ">=", from).
+			OrderBy("random", firestore.Asc).
+			Limit(1).
+			Documents(ctx).
+			GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("error getting random post: %w", err)
+		}
+		if len(docs) == 0 {
+			continue
+		}
+
+		var p models.Post
+		if err := docs[0].DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		p.ID = docs[0].Ref.ID
+		return &p, nil
+	}
+	return nil, ErrNotFound
+}
+
+// GetByID retorna la publicación con el ID indicado, o ErrNotFound si no existe o es un
+// duplicado fusionado en otra.
+func (r *PostRepository) GetByID(ctx context.Context, id string) (*models.Post, error) {
+	p, err := r.GetByIDIncludingMerged(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Merged() {
+		return nil, ErrNotFound
+	}
+	return p, nil
+}
+
+// GetByIDIncludingMerged retorna la publicación como GetByID, pero también si es un duplicado
+// fusionado en otra. Es para las operaciones de administración que deben alcanzar cualquier
+// publicación guardada, como la eliminación forzada.
+func (r *PostRepository) GetByIDIncludingMerged(ctx context.Context, id string) (*models.Post, error) {
+	doc, err := r.db.Collection("posts").Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting post: %w", err)
+	}
+
+	var p models.Post
+	if err := doc.DataTo(&p); err != nil {
+		return nil, fmt.Errorf("error decoding post: %w", err)
+	}
+	p.ID = doc.Ref.ID
+	return &p, nil
+}
+
+// GetByIDs retorna en una sola consulta las publicaciones existentes indexadas por ID.
+// Los IDs que no existen se omiten del resultado.
+func (r *PostRepository) GetByIDs(ctx context.Context, ids []string) (map[string]*models.Post, error) {
+	posts := make(map[string]*models.Post, len(ids))
+	if len(ids) == 0 {
+		return posts, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, r.db.Collection("posts").Doc(id))
+	}
+
+	docs, err := r.db.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("error getting posts: %w", err)
+	}
+	for _, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		posts[p.ID] = &p
+	}
+	return posts, nil
+}
+
+// GetByAnyTag retorna hasta limit publicaciones que comparten al menos una de las etiquetas,
+// de la más reciente a la más antigua.
+func (r *PostRepository) GetByAnyTag(ctx context.Context, tags []string, limit int) ([]*models.Post, error) {
 	iter := r.db.
 		Collection("posts").
+		Where("tags", "array-contains-any", tags).
 		OrderBy("created_at", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc).
+		Limit(limit).
 		Documents(ctx)
 	defer iter.Stop()
 
@@ -36,34 +250,815 @@ func (r *PostRepository) GetAll(ctx context.Context) ([]*models.Post, error) {
 		}
 
 		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+
+		posts = append(posts, &p)
+	}
+	return posts, nil
+}
+
+// GetByTags retorna las publicaciones con alguna de las etiquetas o, si matchAll es true, con
+// todas ellas, de la más reciente a la más antigua. Firestore admite un solo filtro
+// array-contains por consulta, así que con matchAll se consulta por la primera etiqueta y el
+// resto se comprueba al leer cada documento.
+func (r *PostRepository) GetByTags(ctx context.Context, tags []string, matchAll bool) ([]*models.Post, error) {
+	q := r.db.Collection("posts").Where("tags", "array-contains-any", tags)
+	if matchAll {
+		q = r.db.Collection("posts").Where("tags", "array-contains", tags[0])
+	}
+	iter := q.
+		OrderBy("created_at", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	posts := make([]*models.Post, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return posts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating posts: %w", err)
+		}
 
+		var p models.Post
 		if err := doc.DataTo(&p); err != nil {
 			return nil, fmt.Errorf("error decoding post: %w", err)
 		}
+		if p.Merged() || matchAll && !hasAllTags(p.Tags, tags[1:]) {
+			continue
+		}
 		p.ID = doc.Ref.ID
+		posts = append(posts, &p)
+	}
+}
+
+// hasAllTags indica si have contiene cada una de las etiquetas de want.
+func hasAllTags(have, want []string) bool {
+	for _, t := range want {
+		if !slices.Contains(have, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateImage guarda la imagen subida después de crear la publicación y la marca como ya no pendiente.
+func (r *PostRepository) UpdateImage(ctx context.Context, id, url, fallbackURL, publicID string) error {
+	_, err := r.db.Collection("posts").Doc(id).Update(ctx, []firestore.Update{
+		{Path: "image_url", Value: url},
+		{Path: "image_fallback_url", Value: fallbackURL},
+		{Path: "image_public_id", Value: publicID},
+		{Path: "image_pending", Value: false},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ClearImagePending marca la imagen de la publicación como ya no pendiente, sin guardar ninguna,
+// cuando se dejó de intentar subirla.
+func (r *PostRepository) ClearImagePending(ctx context.Context, id string) error {
+	_, err := r.db.Collection("posts").Doc(id).Update(ctx, []firestore.Update{
+		{Path: "image_pending", Value: false},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// UpdateLinkPreview guarda la vista previa del enlace de la publicación.
+func (r *PostRepository) UpdateLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) error {
+	_, err := r.db.Collection("posts").Doc(id).Update(ctx, []firestore.Update{
+		{Path: "link_preview", Value: preview},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// maxSuggestScan es cuántas publicaciones lee como máximo SuggestByTitlePrefix buscando las que
+// cumplen keep, para acotar las lecturas cuando casi ninguna las cumple.
+const maxSuggestScan = 200
 
+// SuggestByTitlePrefix retorna hasta limit publicaciones cuyo título empieza con prefix (sin
+// distinguir mayúsculas) y que cumplen keep, en orden alfabético. Usa una consulta de rango
+// sobre title_lower, que se resuelve con el índice, y solo descarga los campos necesarios para
+// la sugerencia. Las publicaciones se filtran con keep antes de aplicar el límite, leyendo
+// hasta maxSuggestScan.
+func (r *PostRepository) SuggestByTitlePrefix(ctx context.Context, prefix string, limit int, keep func(*models.Post) bool) ([]*models.Post, error) {
+	prefix = strings.ToLower(prefix)
+	iter := r.db.Collection("posts").
+		Select("title", "slug", "author_id", "visibility", "merged_into").
+		Where("title_lower", ">=", prefix).
+		Where("title_lower", "<", prefix+"\uf8ff").
+		OrderBy("title_lower", firestore.Asc).
+		Limit(max(limit, maxSuggestScan)).
+		Documents(ctx)
+	defer iter.Stop()
+
+	posts := make([]*models.Post, 0, limit)
+	for len(posts) < limit {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error suggesting posts: %w", err)
+		}
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		p.ID = doc.Ref.ID
+		if p.Merged() || !keep(&p) {
+			continue
+		}
 		posts = append(posts, &p)
 	}
 	return posts, nil
 }
 
+// GetTagsSince retorna las publicaciones creadas desde since con solo sus etiquetas, autor y
+// visibilidad, para contar etiquetas sin leer el contenido.
+func (r *PostRepository) GetTagsSince(ctx context.Context, since time.Time) ([]*models.Post, error) {
+	iter := r.db.Collection("posts").
+		Select("tags", "author_id", "visibility", "merged_into").
+		Where("created_at", ">=", since).
+		Documents(ctx)
+	defer iter.Stop()
+
+	posts := make([]*models.Post, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return posts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating posts: %w", err)
+		}
+
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		posts = append(posts, &p)
+	}
+}
+
+// UpdateTags reemplaza las etiquetas de la publicación sin modificar el resto de sus campos.
+func (r *PostRepository) UpdateTags(ctx context.Context, id string, tags []string) error {
+	_, err := r.db.Collection("posts").Doc(id).Update(ctx, []firestore.Update{
+		{Path: "tags", Value: tags},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// IncrementViews suma n al contador de vistas de la publicación de forma atómica.
+func (r *PostRepository) IncrementViews(ctx context.Context, id string, n int64) error {
+	_, err := r.db.Collection("posts").Doc(id).Update(ctx, []firestore.Update{
+		{Path: "views", Value: firestore.Increment(n)},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Create guarda una nueva publicación. Si CreatedAt está vacío se usa la fecha actual;
+// UpdatedAt siempre es la fecha actual.
+// Si tiene Slug, lo reserva en la colección "slugs" en la misma transacción, y retorna
+// ErrAlreadyExists sin crear nada si otra publicación ya lo usa. Si es pública, la suma al
+// contador de su mes en el archivo, también en la misma transacción.
 func (r *PostRepository) Create(ctx context.Context, p *models.Post) error {
-	p.CreatedAt = time.Now()
-	doc, _, err := r.db.Collection("posts").Add(ctx, map[string]interface{}{
-		"title":   p.Title,
-		"content": p.Content,
-		//"author_id": p.AuthorID,
+	p.UpdatedAt = time.Now()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = p.UpdatedAt
+	}
+	data := map[string]interface{}{
+		"title":       p.Title,
+		"title_lower": strings.ToLower(p.Title),
+		"content":     p.Content,
+		"author_id":   p.AuthorID,
 		//"tags":      p.Tags,
 		"is_flagged": p.IsFlagged,
 		//"forum_id":  p.ForumID,
-		"likes":     p.Likes,
-		"dislikes":  p.Dislikes,
-		"image_url":  p.ImageURL,
-		"created_at": p.CreatedAt,
+		"likes":              p.Likes,
+		"dislikes":           p.Dislikes,
+		"image_url":          p.ImageURL,
+		"image_fallback_url": p.ImageFallbackURL,
+		"image_public_id":    p.ImagePublicID,
+		"image_pending":      p.ImagePending,
+		"mentions":           p.Mentions,
+		"views":              p.Views,
+		"expires_at":         p.ExpiresAt,
+		"visibility":         p.Visibility,
+		"slug":               p.Slug,
+		"type":               p.Type,
+		"random":             rand.Float64(),
+		"created_at":         p.CreatedAt,
+		"updated_at":         p.UpdatedAt,
+	}
+
+	ref := r.db.Collection("posts").NewDoc()
+	err := r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if p.Slug != "" {
+			if err := tx.Create(r.db.Collection("slugs").Doc(p.Slug), map[string]interface{}{"post_id": ref.ID}); err != nil {
+				return err
+			}
+		}
+		if countsInArchive(p.Visibility) {
+			if err := tx.Set(archiveMonthRef(r.db, p.CreatedAt), archiveDelta(p.CreatedAt, 1), firestore.MergeAll); err != nil {
+				return err
+			}
+		}
+		return tx.Create(ref, data)
 	})
+	if status.Code(err) == codes.AlreadyExists {
+		return ErrAlreadyExists
+	}
 	if err != nil {
 		return err
 	}
-	p.ID = doc.ID
+	p.ID = ref.ID
+	return nil
+}
+
+// GetBySlug retorna la publicación con el slug indicado o ErrNotFound si ninguna lo usa.
+func (r *PostRepository) GetBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	doc, err := r.db.Collection("slugs").Doc(slug).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	postID, _ := doc.Data()["post_id"].(string)
+	if postID == "" {
+		return nil, ErrNotFound
+	}
+	return r.GetByID(ctx, postID)
+}
+
+// SlugInUse indica si el slug está reservado por alguna publicación. A diferencia de GetBySlug,
+// cuenta también los slugs de publicaciones fusionadas, que siguen apuntando a la principal.
+func (r *PostRepository) SlugInUse(ctx context.Context, slug string) (bool, error) {
+	_, err := r.db.Collection("slugs").Doc(slug).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreateMany inserta las publicaciones en un solo envío por lotes, conservando el autor, las
+// etiquetas y la fecha de creación que traen (si CreatedAt está vacío se usa la fecha actual).
+// Retorna un error por publicación, en el mismo orden, nil para las que se guardaron; a esas
+// se les asigna su ID.
+func (r *PostRepository) CreateMany(ctx context.Context, posts []*models.Post) []error {
+	errs := make([]error, len(posts))
+	jobs := make([]*firestore.BulkWriterJob, len(posts))
+	refs := make([]*firestore.DocumentRef, len(posts))
+
+	now := time.Now()
+	bw := r.db.BulkWriter(ctx)
+	for i, p := range posts {
+		if p.CreatedAt.IsZero() {
+			p.CreatedAt = now
+		}
+		p.UpdatedAt = now
+		refs[i] = r.db.Collection("posts").NewDoc()
+		jobs[i], errs[i] = bw.Create(refs[i], map[string]interface{}{
+			"title":       p.Title,
+			"title_lower": strings.ToLower(p.Title),
+			"content":     p.Content,
+			"author_id":   p.AuthorID,
+			"tags":        p.Tags,
+			"is_flagged":  p.IsFlagged,
+			"likes":       p.Likes,
+			"dislikes":    p.Dislikes,
+			"image_url":   p.ImageURL,
+			"mentions":    p.Mentions,
+			"views":       p.Views,
+			"visibility":  p.Visibility,
+			"type":        p.Type,
+			"random":      rand.Float64(),
+			"created_at":  p.CreatedAt,
+			"updated_at":  p.UpdatedAt,
+		})
+	}
+	bw.End()
+
+	created := make([]*models.Post, 0, len(posts))
+	for i, job := range jobs {
+		if errs[i] != nil {
+			continue
+		}
+		if _, err := job.Results(); err != nil {
+			errs[i] = err
+			continue
+		}
+		posts[i].ID = refs[i].ID
+		created = append(created, posts[i])
+	}
+	// las publicaciones ya quedaron guardadas; si el archivo no se actualiza se reconstruye
+	// en la próxima lectura
+	if err := r.addToArchive(ctx, created); err != nil {
+		log.Printf("Error actualizando el archivo tras importar publicaciones: %v", err)
+		if _, err := postsMetaRef(r.db).Update(ctx, []firestore.Update{{Path: "archive_built_at", Value: firestore.Delete}}); err != nil {
+			log.Printf("Error marcando el archivo para reconstruirlo: %v", err)
+		}
+	}
+	return errs
+}
+
+// CountByAuthor retorna cuántas publicaciones ha creado el autor indicado, sin los duplicados
+// fusionados. Firestore no filtra por campos ausentes, así que esos se cuentan aparte y se restan.
+func (r *PostRepository) CountByAuthor(ctx context.Context, authorID string) (int64, error) {
+	q := r.db.Collection("posts").Where("author_id", "==", authorID)
+	res, err := q.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting posts: %w", err)
+	}
+	mergedQuery := q.Where("merged_into", ">", "")
+	merged, err := mergedQuery.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting posts: %w", err)
+	}
+	return aggregationInt(res, "total") - aggregationInt(merged, "total"), nil
+}
+
+// CountByAuthorSince cuenta las publicaciones del autor creadas desde since.
+func (r *PostRepository) CountByAuthorSince(ctx context.Context, authorID string, since time.Time) (int64, error) {
+	q := r.db.Collection("posts").
+		Where("author_id", "==", authorID).
+		Where("created_at", ">=", since)
+	res, err := q.NewAggregationQuery().WithCount("total").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error counting posts: %w", err)
+	}
+	return aggregationInt(res, "total"), nil
+}
+
+// GetByAuthorBetween retorna las publicaciones del autor creadas en [from, to), de la más
+// reciente a la más antigua, con solo los campos de interacciones. from y to en cero no limitan.
+func (r *PostRepository) GetByAuthorBetween(ctx context.Context, authorID string, from, to time.Time) ([]*models.Post, error) {
+	q := r.db.Collection("posts").
+		Select("title", "slug", "author_id", "created_at", "views", "likes", "dislikes", "repost_count", "reactions", "merged_into").
+		Where("author_id", "==", authorID)
+	if !from.IsZero() {
+		q = q.Where("created_at", ">=", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("created_at", "<", to)
+	}
+	docs, err := q.OrderBy("created_at", firestore.Desc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing posts: %w", err)
+	}
+
+	posts := make([]*models.Post, 0, len(docs))
+	for _, doc := range docs {
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		posts = append(posts, &p)
+	}
+	return posts, nil
+}
+
+// GetCreatedBetween retorna las publicaciones creadas en [from, to), de la más antigua a la
+// más reciente.
+func (r *PostRepository) GetCreatedBetween(ctx context.Context, from, to time.Time) ([]*models.Post, error) {
+	docs, err := r.db.Collection("posts").
+		Where("created_at", ">=", from).
+		Where("created_at", "<", to).
+		OrderBy("created_at", firestore.Asc).
+		OrderBy(firestore.DocumentID, firestore.Asc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing posts: %w", err)
+	}
+
+	posts := make([]*models.Post, 0, len(docs))
+	for _, doc := range docs {
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		posts = append(posts, &p)
+	}
+	return posts, nil
+}
+
+// firstCreatedAt retorna, en UTC, el created_at de la primera publicación en el orden dir, o
+// el valor cero si no hay publicaciones.
+func (r *PostRepository) firstCreatedAt(ctx context.Context, dir firestore.Direction) (time.Time, error) {
+	docs, err := r.db.Collection("posts").
+		Select("created_at").
+		OrderBy("created_at", dir).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting post dates: %w", err)
+	}
+	if len(docs) == 0 {
+		return time.Time{}, nil
+	}
+	createdAt, _ := docs[0].Data()["created_at"].(time.Time)
+	return createdAt.UTC(), nil
+}
+
+// SumLikesByAuthor retorna la suma de likes recibidos en todas las publicaciones del autor.
+func (r *PostRepository) SumLikesByAuthor(ctx context.Context, authorID string) (int64, error) {
+	q := r.db.Collection("posts").Where("author_id", "==", authorID)
+	res, err := q.NewAggregationQuery().WithSum("likes", "likes").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error summing likes: %w", err)
+	}
+	return aggregationInt(res, "likes"), nil
+}
+
+// SumInteractionsByAuthor retorna en una sola agregación el total de likes y dislikes
+// recibidos en las publicaciones del autor.
+func (r *PostRepository) SumInteractionsByAuthor(ctx context.Context, authorID string) (likes, dislikes int64, err error) {
+	q := r.db.Collection("posts").Where("author_id", "==", authorID)
+	res, err := q.NewAggregationQuery().
+		WithSum("likes", "likes").
+		WithSum("dislikes", "dislikes").
+		Get(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error summing interactions: %w", err)
+	}
+	return aggregationInt(res, "likes"), aggregationInt(res, "dislikes"), nil
+}
+
+// aggregationInt extrae un valor numérico de un resultado de agregación.
+// Firestore retorna un entero o un double según los valores sumados.
+func aggregationInt(res firestore.AggregationResult, alias string) int64 {
+	v, ok := res[alias].(*firestorepb.Value)
+	if !ok {
+		return 0
+	}
+	if d, isDouble := v.GetValueType().(*firestorepb.Value_DoubleValue); isDouble {
+		return int64(d.DoubleValue)
+	}
+	return v.GetIntegerValue()
+}
+
+// UpdateAuthor reasigna la publicación al autor indicado, moviendo al nuevo autor el karma que
+// le dan sus likes y dislikes.
+func (r *PostRepository) UpdateAuthor(ctx context.Context, id, authorID string) error {
+	ref := r.db.Collection("posts").Doc(id)
+	return r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return err
+		}
+		karma := karmaDeltas{}
+		karma.add(p.AuthorID, -p.Likes, -p.Dislikes)
+		karma.add(authorID, p.Likes, p.Dislikes)
+		authors, err := karma.existingAuthors(r.db, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Update(ref, []firestore.Update{
+			{Path: "author_id", Value: authorID},
+			{Path: "updated_at", Value: time.Now()},
+		}); err != nil {
+			return err
+		}
+		return karma.apply(tx, authors)
+	})
+}
+
+// ReassignAuthor reasigna al autor toID todas las publicaciones de fromID y retorna cuántas
+// se actualizaron. Las escrituras se envían en lote, sin atomicidad entre documentos: si
+// alguna falla, las demás se conservan y puede repetirse la operación. El karma de las que se
+// movieron pasa de fromID a toID al terminar.
+func (r *PostRepository) ReassignAuthor(ctx context.Context, fromID, toID string) (int, error) {
+	docs, err := r.db.Collection("posts").
+		Where("author_id", "==", fromID).
+		Select("likes", "dislikes").
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("error listing posts: %w", err)
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	bw := r.db.BulkWriter(ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(docs))
+	for _, doc := range docs {
+		job, err := bw.Update(doc.Ref, []firestore.Update{
+			{Path: "author_id", Value: toID},
+			{Path: "updated_at", Value: now},
+		})
+		if err != nil {
+			bw.End()
+			return 0, fmt.Errorf("error queuing post update: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	bw.End()
+
+	updated := 0
+	var moved int64
+	var firstErr error
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		updated++
+		var p models.Post
+		if err := docs[i].DataTo(&p); err != nil {
+			return updated, fmt.Errorf("error decoding post: %w", err)
+		}
+		moved += models.ComputeKarma(int64(p.Likes), int64(p.Dislikes))
+	}
+	if err := r.moveKarma(ctx, fromID, toID, moved); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if firstErr != nil {
+		return updated, fmt.Errorf("error updating posts: %w", firstErr)
+	}
+	return updated, nil
+}
+
+// moveKarma pasa karma de fromID a toID. Los usuarios que ya no existen se omiten.
+func (r *PostRepository) moveKarma(ctx context.Context, fromID, toID string, karma int64) error {
+	if karma == 0 {
+		return nil
+	}
+	for id, delta := range map[string]int64{fromID: -karma, toID: karma} {
+		_, err := r.db.Collection("users").Doc(id).Update(ctx, []firestore.Update{{Path: "karma", Value: firestore.Increment(delta)}})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("error updating karma: %w", err)
+		}
+	}
 	return nil
 }
+
+// GetByImagePublicID retorna la publicación cuya imagen tiene el public ID de Cloudinary
+// indicado, o ErrNotFound si ninguna la usa.
+func (r *PostRepository) GetByImagePublicID(ctx context.Context, publicID string) (*models.Post, error) {
+	docs, err := r.db.Collection("posts").
+		Where("image_public_id", "==", publicID).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting post by image: %w", err)
+	}
+	if len(docs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	var p models.Post
+	if err := docs[0].DataTo(&p); err != nil {
+		return nil, fmt.Errorf("error decoding post: %w", err)
+	}
+	if p.Merged() {
+		return nil, ErrNotFound
+	}
+	p.ID = docs[0].Ref.ID
+	return &p, nil
+}
+
+// UpdateImageModeration guarda el resultado de la moderación de la imagen y, si flag es true,
+// marca la publicación como reportada para que la revise un moderador.
+func (r *PostRepository) UpdateImageModeration(ctx context.Context, id, result string, flag bool) error {
+	updates := []firestore.Update{
+		{Path: "image_moderation", Value: result},
+		{Path: "updated_at", Value: time.Now()},
+	}
+	if flag {
+		updates = append(updates, firestore.Update{Path: "is_flagged", Value: true})
+	}
+	_, err := r.db.Collection("posts").Doc(id).Update(ctx, updates)
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// GetFlagged retorna las publicaciones reportadas, de la más antigua a la más reciente.
+func (r *PostRepository) GetFlagged(ctx context.Context) ([]*models.Post, error) {
+	docs, err := r.db.Collection("posts").
+		Where("is_flagged", "==", true).
+		OrderBy("created_at", firestore.Asc).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing flagged posts: %w", err)
+	}
+
+	posts := make([]*models.Post, 0, len(docs))
+	for _, doc := range docs {
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		posts = append(posts, &p)
+	}
+	return posts, nil
+}
+
+// ClearFlags quita la marca is_flagged de las publicaciones indicadas en un solo envío por lotes
+// y las marca como revisadas en reviewedAt.
+// Retorna el resultado de cada ID: nil si se actualizó o ErrNotFound si no existe.
+func (r *PostRepository) ClearFlags(ctx context.Context, ids []string, reviewedAt time.Time) (map[string]error, error) {
+	bw := r.db.BulkWriter(ctx)
+	jobs := make(map[string]*firestore.BulkWriterJob, len(ids))
+	for _, id := range ids {
+		job, err := bw.Update(r.db.Collection("posts").Doc(id), []firestore.Update{
+			{Path: "is_flagged", Value: false},
+			{Path: "reviewed_at", Value: reviewedAt},
+			{Path: "updated_at", Value: reviewedAt},
+		})
+		if err != nil {
+			bw.End()
+			return nil, fmt.Errorf("error queuing post update: %w", err)
+		}
+		jobs[id] = job
+	}
+	bw.End()
+
+	results := make(map[string]error, len(jobs))
+	for id, job := range jobs {
+		_, err := job.Results()
+		if status.Code(err) == codes.NotFound {
+			err = ErrNotFound
+		}
+		results[id] = err
+	}
+	return results, nil
+}
+
+// GetExpired retorna hasta limit publicaciones cuyo expires_at ya pasó respecto a now.
+// Las publicaciones sin fecha de expiración no se incluyen.
+func (r *PostRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*models.Post, error) {
+	docs, err := r.db.Collection("posts").
+		Where("expires_at", "<=", now).
+		OrderBy("expires_at", firestore.Asc).
+		Limit(limit).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error listing expired posts: %w", err)
+	}
+
+	posts := make([]*models.Post, 0, len(docs))
+	for _, doc := range docs {
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return nil, fmt.Errorf("error decoding post: %w", err)
+		}
+		if p.Merged() {
+			continue
+		}
+		p.ID = doc.Ref.ID
+		posts = append(posts, &p)
+	}
+	return posts, nil
+}
+
+// Delete elimina definitivamente la publicación, la descuenta del archivo y del karma de su
+// autor, y libera su slug. Retorna ErrNotFound si ya no existe.
+func (r *PostRepository) Delete(ctx context.Context, id string) error {
+	ref := r.db.Collection("posts").Doc(id)
+	return r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var p models.Post
+		if err := doc.DataTo(&p); err != nil {
+			return err
+		}
+		// los likes y dislikes de la publicación dejan de contar en el karma del autor
+		karma := karmaDeltas{}
+		karma.add(p.AuthorID, -p.Likes, -p.Dislikes)
+		authors, err := karma.existingAuthors(r.db, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(ref); err != nil {
+			return err
+		}
+		if err := karma.apply(tx, authors); err != nil {
+			return err
+		}
+		// los duplicados fusionados ya se descontaron del archivo al fusionarlos
+		if !p.Merged() && countsInArchive(p.Visibility) {
+			if err := tx.Set(archiveMonthRef(r.db, p.CreatedAt), archiveDelta(p.CreatedAt, -1), firestore.MergeAll); err != nil {
+				return err
+			}
+		}
+		// la publicación desaparece del listado, que cambia aunque no quede otra más nueva
+		if err := tx.Set(postsMetaRef(r.db), map[string]interface{}{"deleted_at": time.Now()}, firestore.MergeAll); err != nil {
+			return err
+		}
+		// liberar el slug para que otra publicación pueda usarlo
+		if p.Slug != "" && !p.Merged() {
+			return tx.Delete(r.db.Collection("slugs").Doc(p.Slug))
+		}
+		return nil
+	})
+}
+
+// postsMetaRef es el documento con datos de la colección "posts" que no pertenecen a ninguna
+// publicación, como la fecha de la última eliminación.
+func postsMetaRef(db *firestore.Client) *firestore.DocumentRef {
+	return db.Collection("meta").Doc("posts")
+}
+
+// LastModified retorna la fecha del último cambio en las publicaciones: la mayor entre el
+// updated_at más reciente, el created_at más reciente (las publicaciones antiguas pueden no
+// tener updated_at) y la última eliminación. Retorna el valor cero si nunca hubo publicaciones.
+// Los cambios de vistas no cuentan.
+func (r *PostRepository) LastModified(ctx context.Context) (time.Time, error) {
+	latest, err := r.firstCreatedAt(ctx, firestore.Desc)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	docs, err := r.db.Collection("posts").
+		Select("updated_at").
+		OrderBy("updated_at", firestore.Desc).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting post dates: %w", err)
+	}
+	if len(docs) > 0 {
+		if updatedAt, _ := docs[0].Data()["updated_at"].(time.Time); updatedAt.After(latest) {
+			latest = updatedAt
+		}
+	}
+
+	meta, err := postsMetaRef(r.db).Get(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return time.Time{}, fmt.Errorf("error getting posts metadata: %w", err)
+	}
+	if err == nil {
+		if deletedAt, _ := meta.Data()["deleted_at"].(time.Time); deletedAt.After(latest) {
+			latest = deletedAt
+		}
+	}
+	return latest.UTC(), nil
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// newEmulatorClient conecta con el emulador de Firestore de FIRESTORE_EMULATOR_HOST, o salta el
+// test si no está configurado.
+func newEmulatorClient(t *testing.T) *firestore.Client {
+	t.Helper()
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST no está definido; se necesita el emulador de Firestore")
+	}
+	client, err := firestore.NewClient(context.Background(), "talkus-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestGetAllOrdersTiesByID(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPostRepository(newEmulatorClient(t))
+
+	// mismas fechas, como deja una importación por lotes
+	createdAt := time.Date(2024, 5, 1, 13, 4, 5, 0, time.UTC)
+	posts := make([]*models.Post, 5)
+	for i := range posts {
+		posts[i] = &models.Post{Title: "empate", Content: "contenido", CreatedAt: createdAt}
+	}
+	for i, err := range repo.CreateMany(ctx, posts) {
+		if err != nil {
+			t.Fatalf("CreateMany[%d]: %v", i, err)
+		}
+	}
+	created := make(map[string]bool, len(posts))
+	for _, p := range posts {
+		created[p.ID] = true
+		t.Cleanup(func() { repo.Delete(ctx, p.ID) })
+	}
+
+	// solo importan las publicaciones de este test, aunque el emulador tenga otras
+	listIDs := func() []string {
+		all, err := repo.GetAll(ctx, models.PostFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, p := range all {
+			if created[p.ID] {
+				ids = append(ids, p.ID)
+			}
+		}
+		return ids
+	}
+	first, second := listIDs(), listIDs()
+
+	if len(first) != len(posts) {
+		t.Fatalf("GetAll retornó %d de las %d publicaciones creadas", len(first), len(posts))
+	}
+	if !slices.Equal(first, second) {
+		t.Fatalf("el orden cambió entre consultas:\n%v\n%v", first, second)
+	}
+	// el desempate es por ID, en el mismo sentido que la fecha
+	if !slices.IsSortedFunc(first, func(a, b string) int { return strings.Compare(b, a) }) {
+		t.Fatalf("los empates no se ordenan por ID descendente: %v", first)
+	}
+}
+
+func TestSuggestByTitlePrefixFiltersBeforeLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPostRepository(newEmulatorClient(t))
+
+	// un prefijo propio del test, para no mezclarse con otras publicaciones del emulador
+	prefix := fmt.Sprintf("Sugerencia %d ", time.Now().UnixNano())
+	posts := []*models.Post{
+		{Title: prefix + "a", Visibility: models.VisibilityPrivate},
+		{Title: prefix + "b", Visibility: models.VisibilityFollowers},
+		{Title: prefix + "c", Visibility: models.VisibilityPublic},
+		{Title: prefix + "d"},
+		{Title: prefix + "e", Visibility: models.VisibilityPublic},
+	}
+	for i, err := range repo.CreateMany(ctx, posts) {
+		if err != nil {
+			t.Fatalf("CreateMany[%d]: %v", i, err)
+		}
+	}
+	for _, p := range posts {
+		t.Cleanup(func() { repo.Delete(ctx, p.ID) })
+	}
+
+	public := func(p *models.Post) bool {
+		return p.Visibility == "" || p.Visibility == models.VisibilityPublic
+	}
+	got, err := repo.SuggestByTitlePrefix(ctx, prefix, 2, public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, p := range got {
+		titles = append(titles, p.Title)
+	}
+	// las dos primeras en orden alfabético no son públicas y no deben ocupar el límite
+	if want := []string{prefix + "c", prefix + "d"}; !slices.Equal(titles, want) {
+		t.Fatalf("sugerencias = %q, se esperaba %q", titles, want)
+	}
+}
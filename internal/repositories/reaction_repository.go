@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReactionRepository se encarga de interactuar con la colección "post_reactions" en Firestore.
+type ReactionRepository struct {
+	db *firestore.Client
+}
+
+// NewReactionRepository crea una nueva instancia del repositorio.
+func NewReactionRepository(db *firestore.Client) *ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+// reactionField retorna el campo de la publicación donde se cuenta la reacción.
+func reactionField(reaction string) string {
+	switch reaction {
+	case models.ReactionLike:
+		return "likes"
+	case models.ReactionDislike:
+		return "dislikes"
+	default:
+		return "reactions." + reaction
+	}
+}
+
+// React guarda la reacción del usuario a la publicación, reemplazando la anterior si la había,
+// y ajusta los contadores de la publicación y el karma de su autor en la misma transacción.
+// Retorna los totales por reacción resultantes, o ErrNotFound si la publicación no existe.
+func (r *ReactionRepository) React(ctx context.Context, postID, userID, reaction string) (map[string]int, error) {
+	postRef := r.db.Collection("posts").Doc(postID)
+	// un documento por usuario y publicación garantiza una sola reacción de cada uno
+	reactionRef := r.db.Collection("post_reactions").Doc(postID + "_" + userID)
+
+	var tallies map[string]int
+	err := r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		postDoc, err := tx.Get(postRef)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var post models.Post
+		if err := postDoc.DataTo(&post); err != nil {
+			return err
+		}
+		if post.Merged() {
+			return ErrNotFound
+		}
+
+		previous := ""
+		reactionDoc, err := tx.Get(reactionRef)
+		switch {
+		case err == nil:
+			previous, _ = reactionDoc.Data()["type"].(string)
+		case status.Code(err) != codes.NotFound:
+			return err
+		}
+
+		tallies = reactionTallies(&post)
+		if previous == reaction {
+			return nil
+		}
+
+		updates := []firestore.Update{
+			{Path: reactionField(reaction), Value: firestore.Increment(1)},
+			{Path: "updated_at", Value: time.Now()},
+		}
+		tallies[reaction]++
+		if previous != "" {
+			updates = append(updates, firestore.Update{Path: reactionField(previous), Value: firestore.Increment(-1)})
+			tallies[previous]--
+		}
+
+		karma := karmaDeltas{}
+		karma.add(post.AuthorID,
+			tallies[models.ReactionLike]-post.Likes, tallies[models.ReactionDislike]-post.Dislikes)
+		authors, err := karma.existingAuthors(r.db, tx)
+		if err != nil {
+			return err
+		}
+		if err := karma.apply(tx, authors); err != nil {
+			return err
+		}
+
+		if err := tx.Set(reactionRef, models.PostReaction{
+			PostID:    postID,
+			UserID:    userID,
+			Type:      reaction,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+		return tx.Update(postRef, updates)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tallies, nil
+}
+
+// CountLikesSince retorna, por ID de publicación, cuántos likes vigentes se dieron desde since.
+// Un like cuenta según la fecha en que el usuario lo dejó; si luego lo cambió por otra
+// reacción, ya no cuenta.
+func (r *ReactionRepository) CountLikesSince(ctx context.Context, since time.Time) (map[string]int, error) {
+	iter := r.db.Collection("post_reactions").
+		Select("post_id").
+		Where("type", "==", models.ReactionLike).
+		Where("created_at", ">=", since).
+		Documents(ctx)
+	defer iter.Stop()
+
+	counts := make(map[string]int)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			return counts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating reactions: %w", err)
+		}
+		if postID, _ := doc.Data()["post_id"].(string); postID != "" {
+			counts[postID]++
+		}
+	}
+}
+
+// reactionTallies junta en un solo mapa los contadores de like/dislike y las demás reacciones.
+func reactionTallies(p *models.Post) map[string]int {
+	tallies := make(map[string]int, len(p.Reactions)+2)
+	for k, v := range p.Reactions {
+		tallies[k] = v
+	}
+	tallies[models.ReactionLike] = p.Likes
+	tallies[models.ReactionDislike] = p.Dislikes
+	return tallies
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+func TestReactKeepsAuthorKarma(t *testing.T) {
+	ctx := context.Background()
+	db := newEmulatorClient(t)
+	posts := NewPostRepository(db)
+	reactions := NewReactionRepository(db)
+
+	authorID := fmt.Sprintf("karma-%d", time.Now().UnixNano())
+	author := db.Collection("users").Doc(authorID)
+	if _, err := author.Set(ctx, map[string]interface{}{"username": "autor", "karma": 0}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { author.Delete(ctx) })
+
+	post := &models.Post{Title: "karma", AuthorID: authorID}
+	if err := posts.Create(ctx, post); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { posts.Delete(ctx, post.ID) })
+
+	karma := func() int64 {
+		t.Helper()
+		doc, err := author.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, _ := doc.Data()["karma"].(int64)
+		return k
+	}
+	steps := []struct {
+		userID, reaction string
+		want             int64
+	}{
+		{"u1", models.ReactionLike, 1},
+		{"u2", models.ReactionLike, 2},
+		// repetir la misma reacción no cambia nada
+		{"u2", models.ReactionLike, 2},
+		{"u1", models.ReactionDislike, 0},
+		// las demás reacciones no cuentan en el karma
+		{"u2", models.ReactionLove, -1},
+	}
+	for _, step := range steps {
+		if _, err := reactions.React(ctx, post.ID, step.userID, step.reaction); err != nil {
+			t.Fatal(err)
+		}
+		if got := karma(); got != step.want {
+			t.Fatalf("tras %s de %s: karma = %d, se esperaba %d", step.reaction, step.userID, got, step.want)
+		}
+	}
+
+	// al eliminar la publicación sus reacciones dejan de contar
+	if err := posts.Delete(ctx, post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := karma(); got != 0 {
+		t.Fatalf("tras eliminar la publicación: karma = %d, se esperaba 0", got)
+	}
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RepostRepository se encarga de interactuar con la colección "reposts" en Firestore.
+type RepostRepository struct {
+	db *firestore.Client
+}
+
+// NewRepostRepository crea una nueva instancia del repositorio.
+func NewRepostRepository(db *firestore.Client) *RepostRepository {
+	return &RepostRepository{db: db}
+}
+
+// Create guarda el repost e incrementa el contador repost_count de la publicación original
+// en una misma transacción. Retorna ErrNotFound si la publicación original ya no existe.
+func (r *RepostRepository) Create(ctx context.Context, rp *models.Repost) error {
+	postRef := r.db.Collection("posts").Doc(rp.OriginalPostID)
+	repostRef := r.db.Collection("reposts").NewDoc()
+
+	err := r.db.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(postRef)
+		if status.Code(err) == codes.NotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		// un duplicado fusionado ya no existe para los usuarios
+		if mergedInto, _ := doc.Data()["merged_into"].(string); mergedInto != "" {
+			return ErrNotFound
+		}
+
+		if err := tx.Create(repostRef, rp); err != nil {
+			return err
+		}
+		return tx.Update(postRef, []firestore.Update{
+			{Path: "repost_count", Value: firestore.Increment(1)},
+			{Path: "updated_at", Value: time.Now()},
+		})
+	})
+	if err != nil {
+		return err
+	}
+	rp.ID = repostRef.ID
+	return nil
+}
+
+// GetByUser retorna los reposts del usuario ordenados del más reciente al más antiguo.
+func (r *RepostRepository) GetByUser(ctx context.Context, userID string) ([]*models.Repost, error) {
+	iter := r.db.
+		Collection("reposts").
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Desc).
+		Documents(ctx)
+	defer iter.Stop()
+
+	reposts := make([]*models.Repost, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error iterating reposts: %w", err)
+		}
+
+		var rp models.Repost
+		if err := doc.DataTo(&rp); err != nil {
+			return nil, fmt.Errorf("error decoding repost: %w", err)
+		}
+		rp.ID = doc.Ref.ID
+
+		reposts = append(reposts, &rp)
+	}
+	return reposts, nil
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // UserRepository se encarga de interactuar con la colección "users" en Firestore.
@@ -20,6 +24,9 @@ func NewUserRepository(db *firestore.Client) *UserRepository {
 // GetUserByID busca y retorna el documento del usuario por ID.
 func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (map[string]interface{}, error) {
 	doc, err := r.db.Collection("users").Doc(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo usuario: %w", err)
 	}
@@ -31,3 +38,127 @@ func (r *UserRepository) CreateUser(ctx context.Context, userID string, userData
 	_, err := r.db.Collection("users").Doc(userID).Set(ctx, userData)
 	return err
 }
+
+// GetIDsByUsernames resuelve nombres de usuario a sus IDs. Los nombres que no existen se omiten.
+func (r *UserRepository) GetIDsByUsernames(ctx context.Context, usernames []string) (map[string]string, error) {
+	ids := make(map[string]string, len(usernames))
+
+	// Firestore limita a 30 los valores de un filtro "in"
+	const chunkSize = 30
+	for start := 0; start < len(usernames); start += chunkSize {
+		end := min(start+chunkSize, len(usernames))
+
+		docs, err := r.db.Collection("users").
+			Where("username", "in", usernames[start:end]).
+			Documents(ctx).
+			GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("error buscando usuarios: %w", err)
+		}
+		for _, doc := range docs {
+			if username, ok := doc.Data()["username"].(string); ok {
+				ids[username] = doc.Ref.ID
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ListIDs retorna los IDs de todos los usuarios sin descargar sus documentos completos.
+func (r *UserRepository) ListIDs(ctx context.Context) ([]string, error) {
+	iter := r.db.Collection("users").Select().Documents(ctx)
+	defer iter.Stop()
+
+	ids := make([]string, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listando usuarios: %w", err)
+		}
+		ids = append(ids, doc.Ref.ID)
+	}
+	return ids, nil
+}
+
+// UpdateKarma guarda el karma calculado del usuario.
+func (r *UserRepository) UpdateKarma(ctx context.Context, userID string, karma int64) error {
+	_, err := r.db.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "karma", Value: karma},
+	})
+	return err
+}
+
+// GetUsersByIDs retorna los documentos de los usuarios indicados, por ID, en una sola lectura.
+// Los usuarios que no existen se omiten.
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, ids []string) (map[string]map[string]interface{}, error) {
+	users := make(map[string]map[string]interface{}, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, r.db.Collection("users").Doc(id))
+	}
+
+	docs, err := r.db.GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo usuarios: %w", err)
+	}
+	for _, doc := range docs {
+		if doc.Exists() {
+			users[doc.Ref.ID] = doc.Data()
+		}
+	}
+	return users, nil
+}
+
+// GetPreferences retorna las preferencias guardadas del usuario, vacías si nunca las configuró.
+// Retorna ErrNotFound si el usuario no existe.
+func (r *UserRepository) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	doc, err := r.db.Collection("users").Doc(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo usuario: %w", err)
+	}
+
+	var user struct {
+		Preferences models.UserPreferences `firestore:"preferences"`
+	}
+	if err := doc.DataTo(&user); err != nil {
+		return nil, fmt.Errorf("error leyendo preferencias: %w", err)
+	}
+	return &user.Preferences, nil
+}
+
+// UpdatePreferences reemplaza las preferencias del usuario. Retorna ErrNotFound si no existe.
+func (r *UserRepository) UpdatePreferences(ctx context.Context, userID string, prefs *models.UserPreferences) error {
+	_, err := r.db.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{Path: "preferences", Value: prefs},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// SetMuted agrega (muted true) o quita mutedID de los usuarios silenciados por userID, sin
+// reemplazar el resto de sus preferencias. Retorna ErrNotFound si userID no existe.
+func (r *UserRepository) SetMuted(ctx context.Context, userID, mutedID string, muted bool) error {
+	var value interface{} = firestore.ArrayRemove(mutedID)
+	if muted {
+		value = firestore.ArrayUnion(mutedID)
+	}
+	_, err := r.db.Collection("users").Doc(userID).Update(ctx, []firestore.Update{
+		{FieldPath: firestore.FieldPath{"preferences", "muted_users"}, Value: value},
+	})
+	if status.Code(err) == codes.NotFound {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// AssetLister busca recursos de Cloudinary por antigüedad.
+type AssetLister interface {
+	// ListCreatedBefore retorna el PublicID de los recursos de folder creados antes de before.
+	ListCreatedBefore(ctx context.Context, folder string, before time.Time) ([]string, error)
+}
+
+// AssetJanitor elimina recursos temporales de Cloudinary después de un tiempo. Las
+// eliminaciones programadas con ScheduleDestroy viven en memoria; los recursos que queden tras
+// un reinicio los elimina Run, que revisa periódicamente la carpeta por antigüedad.
+type AssetJanitor struct {
+	uploader ImageUploader
+	lister   AssetLister
+}
+
+func NewAssetJanitor(uploader ImageUploader, lister AssetLister) *AssetJanitor {
+	return &AssetJanitor{uploader: uploader, lister: lister}
+}
+
+// ScheduleDestroy elimina el recurso publicID cuando pase el tiempo indicado.
+func (j *AssetJanitor) ScheduleDestroy(publicID string, after time.Duration) {
+	time.AfterFunc(after, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := j.uploader.Destroy(ctx, publicID); err != nil {
+			log.Printf("Error eliminando recurso temporal %s: %v", publicID, err)
+		}
+	})
+}
+
+// Sweep elimina los recursos de folder con más de maxAge de antigüedad y retorna cuántos
+// eliminó. Si uno no se puede eliminar lo registra y sigue con los demás.
+func (j *AssetJanitor) Sweep(ctx context.Context, folder string, maxAge time.Duration) (int, error) {
+	ids, err := j.lister.ListCreatedBefore(ctx, folder, time.Now().Add(-maxAge))
+	if err != nil {
+		return 0, err
+	}
+	destroyed := 0
+	for _, id := range ids {
+		if err := j.uploader.Destroy(ctx, id); err != nil {
+			log.Printf("Error eliminando recurso temporal %s: %v", id, err)
+			continue
+		}
+		destroyed++
+	}
+	return destroyed, nil
+}
+
+// Run ejecuta Sweep sobre folder cada interval hasta que ctx se cancela.
+func (j *AssetJanitor) Run(ctx context.Context, folder string, maxAge, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n, err := j.Sweep(ctx, folder, maxAge)
+			if err != nil {
+				log.Printf("Error revisando los recursos temporales de %s: %v", folder, err)
+			} else if n > 0 {
+				log.Printf("%d recursos temporales de %s eliminados", n, folder)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fixedLister retorna siempre los mismos recursos y registra la fecha límite pedida.
+type fixedLister struct {
+	ids    []string
+	before time.Time
+}
+
+func (l *fixedLister) ListCreatedBefore(ctx context.Context, folder string, before time.Time) ([]string, error) {
+	l.before = before
+	return l.ids, nil
+}
+
+func TestSweepDestroysAssetsOlderThanMaxAge(t *testing.T) {
+	u := &recordingUploader{}
+	l := &fixedLister{ids: []string{"previews/preview_1", "previews/preview_2"}}
+	j := NewAssetJanitor(u, l)
+
+	n, err := j.Sweep(context.Background(), PreviewFolder, 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || len(u.destroyed) != 2 {
+		t.Fatalf("eliminados = %d (%v), se esperaban los 2 recursos listados", n, u.destroyed)
+	}
+	if age := time.Since(l.before); age < 30*time.Minute || age > 31*time.Minute {
+		t.Fatalf("se listaron los recursos anteriores a hace %v, se esperaban 30m", age)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"firebase.google.com/go/v4/auth"
@@ -15,6 +16,9 @@ import (
 	"github.com/JuanPidarraga/talkus-backend/internal/models"
 )
 
+// ErrEmailAlreadyExists indica que ya existe un usuario registrado con el mismo correo (sin importar mayúsculas).
+var ErrEmailAlreadyExists = errors.New("el correo ya está registrado")
+
 type AuthService struct {
 	firebase *config.FirebaseApp
 }
@@ -64,6 +68,9 @@ func (s *AuthService) RegisterUser(ctx context.Context, username, email, passwor
 
 	// Crear el usuario en Firebase Authentication
 	userRecord, err := s.firebase.Auth.CreateUser(ctx, params)
+	if auth.IsEmailAlreadyExists(err) {
+		return nil, ErrEmailAlreadyExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -71,13 +78,32 @@ func (s *AuthService) RegisterUser(ctx context.Context, username, email, passwor
 	return userRecord, nil
 }
 
+// normalizeEmail retorna la forma canónica del correo usada para verificar duplicados.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// emailExists verifica si algún usuario guardado en Firestore ya usa el correo normalizado.
+func (s *AuthService) emailExists(ctx context.Context, normalizedEmail string) (bool, error) {
+	docs, err := s.firebase.Firestore.Collection("users").
+		Where("emailNormalized", "==", normalizedEmail).
+		Limit(1).
+		Documents(ctx).
+		GetAll()
+	if err != nil {
+		return false, err
+	}
+	return len(docs) > 0, nil
+}
+
 func (s *AuthService) SaveUserInFirestore(ctx context.Context, user *auth.UserRecord) error {
-	// Define el documento a almacenar
+	// Define el documento a almacenar; se guarda el correo tal como se registró y su forma normalizada
 	doc := map[string]interface{}{
-		"uid":       user.UID,
-		"username":  user.DisplayName,
-		"email":     user.Email,
-		"createdAt": time.Now(),
+		"uid":             user.UID,
+		"username":        user.DisplayName,
+		"email":           user.Email,
+		"emailNormalized": normalizeEmail(user.Email),
+		"createdAt":       time.Now(),
 	}
 
 	// Guarda el documento en la colección "users", usando el UID como documento ID
@@ -86,6 +112,15 @@ func (s *AuthService) SaveUserInFirestore(ctx context.Context, user *auth.UserRe
 }
 
 func (s *AuthService) RegisterAndSaveUser(ctx context.Context, username string, email, password string) (*auth.UserRecord, error) {
+	// 0. Rechazar correos ya registrados con otra combinación de mayúsculas
+	exists, err := s.emailExists(ctx, normalizeEmail(email))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEmailAlreadyExists
+	}
+
 	// 1. Crear el usuario en Firebase Auth
 	userRecord, err := s.RegisterUser(ctx, username, email, password)
 	if err != nil {
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"firebase.google.com/go/v4/auth"
+	"github.com/JuanPidarraga/talkus-backend/config"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	for _, email := range []string{"ana@example.com", "Ana@Example.com", "ANA@EXAMPLE.COM", "  ana@Example.com "} {
+		if got := normalizeEmail(email); got != "ana@example.com" {
+			t.Errorf("normalizeEmail(%q) = %q, se esperaba %q", email, got, "ana@example.com")
+		}
+	}
+}
+
+func TestRegisterRejectsMixedCaseDuplicateEmail(t *testing.T) {
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST no está definido; se necesita el emulador de Firestore")
+	}
+	ctx := context.Background()
+	db, err := firestore.NewClient(ctx, "talkus-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// sin cliente de Auth: el duplicado debe detectarse antes de crear el usuario en Firebase
+	s := NewAuthService(&config.FirebaseApp{Firestore: db})
+	domain := fmt.Sprintf("dup%d.example.com", time.Now().UnixNano())
+	existing := &auth.UserRecord{UserInfo: &auth.UserInfo{UID: "uid-" + domain, DisplayName: "ana", Email: "Ana.Perez@" + domain}}
+	if err := s.SaveUserInFirestore(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Collection("users").Doc(existing.UID).Delete(ctx) })
+
+	for _, email := range []string{"ana.perez@" + domain, "ANA.PEREZ@" + domain, " Ana.Perez@" + domain + " "} {
+		if _, err := s.RegisterAndSaveUser(ctx, "otra", email, "secreto123"); !errors.Is(err, ErrEmailAlreadyExists) {
+			t.Errorf("RegisterAndSaveUser(%q) error = %v, se esperaba ErrEmailAlreadyExists", email, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature indica que la firma de la notificación falta o no coincide.
+	ErrInvalidSignature = errors.New("firma de la notificación inválida")
+	// ErrSignatureExpired indica que la notificación es más antigua de lo permitido.
+	ErrSignatureExpired = errors.New("la notificación expiró")
+)
+
+// NotificationVerifier verifica las notificaciones que Cloudinary envía a la URL configurada.
+// Cloudinary firma cada una con el hash de cuerpo + X-Cld-Timestamp + API secret, en hexadecimal
+// en la cabecera X-Cld-Signature.
+type NotificationVerifier struct {
+	secret  string
+	newHash func() hash.Hash
+	maxAge  time.Duration
+}
+
+// NewNotificationVerifier crea el verificador. algorithm es "sha256" o, por defecto, "sha1".
+// Con maxAge en 0 no se limita la antigüedad de las notificaciones.
+func NewNotificationVerifier(secret, algorithm string, maxAge time.Duration) *NotificationVerifier {
+	newHash := sha1.New
+	if algorithm == "sha256" {
+		newHash = sha256.New
+	}
+	return &NotificationVerifier{secret: secret, newHash: newHash, maxAge: maxAge}
+}
+
+// Verify comprueba la firma del cuerpo recibido. Retorna ErrInvalidSignature si falta, no
+// coincide o no hay secret configurado, y ErrSignatureExpired si timestamp (segundos Unix) es
+// demasiado antiguo.
+func (v *NotificationVerifier) Verify(body []byte, timestamp, signature string) error {
+	if v.secret == "" || timestamp == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	h := v.newHash()
+	h.Write(body)
+	h.Write([]byte(timestamp))
+	h.Write([]byte(v.secret))
+	expected := hex.EncodeToString(h.Sum(nil))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+
+	// la antigüedad se revisa después de la firma para no confiar en un timestamp no firmado
+	if v.maxAge > 0 && time.Since(time.Unix(sec, 0)) > v.maxAge {
+		return ErrSignatureExpired
+	}
+	return nil
+}
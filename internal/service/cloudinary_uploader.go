@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/tracing"
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// ErrUploadTimeout indica que la subida no terminó dentro del tiempo máximo configurado.
+var ErrUploadTimeout = errors.New("la subida de la imagen excedió el tiempo máximo")
+
+// ErrFolderNotAllowed indica un intento de subir a una carpeta fuera de la lista permitida.
+var ErrFolderNotAllowed = errors.New("carpeta de Cloudinary no permitida")
+
+// ErrPublicIDTaken indica que ya existe una imagen con el PublicID pedido y no se permitió
+// reemplazarla.
+var ErrPublicIDTaken = errors.New("ya existe una imagen con ese identificador")
+
+// ErrUploaderUnavailable indica que no se pudo comunicar con Cloudinary, a diferencia de un
+// rechazo de la imagen reportado por el servicio.
+var ErrUploaderUnavailable = errors.New("el servicio de imágenes no está disponible")
+
+// CloudinaryUploader implementa ImageUploader sobre Cloudinary.
+type CloudinaryUploader struct {
+	cld *cloudinary.Cloudinary
+	// uploadTimeout limita cada subida, sin importar el plazo de la petición que la origina.
+	uploadTimeout time.Duration
+	// allowedFolders son las únicas carpetas en las que se sube, para que un error que deje
+	// la carpeta en manos del usuario no permita escribir en otra parte de la cuenta.
+	allowedFolders map[string]bool
+}
+
+var _ ImageUploader = (*CloudinaryUploader)(nil)
+
+func NewCloudinaryUploader(cld *cloudinary.Cloudinary, uploadTimeout time.Duration, allowedFolders []string) *CloudinaryUploader {
+	allowed := make(map[string]bool, len(allowedFolders))
+	for _, f := range allowedFolders {
+		allowed[f] = true
+	}
+	return &CloudinaryUploader{cld: cld, uploadTimeout: uploadTimeout, allowedFolders: allowed}
+}
+
+// Upload sube la imagen con su propio plazo de uploadTimeout, independiente del de ctx. Al
+// vencer se cancela la petición HTTP a Cloudinary y se retorna ErrUploadTimeout. Retorna
+// ErrFolderNotAllowed sin subir nada si params.Folder no está en la lista permitida, y
+// ErrPublicIDTaken si el PublicID ya está en uso y params.Overwrite es false.
+func (u *CloudinaryUploader) Upload(ctx context.Context, file io.Reader, params ImageUploadParams) (_ *ImageUploadResult, err error) {
+	ctx, span := tracing.Start(ctx, "Cloudinary.Upload",
+		attribute.String("cloudinary.folder", params.Folder),
+		attribute.String("cloudinary.public_id", params.PublicID))
+	defer func() { tracing.End(span, err) }()
+
+	if !u.allowedFolders[params.Folder] {
+		return nil, fmt.Errorf("%w: %q", ErrFolderNotAllowed, params.Folder)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), u.uploadTimeout)
+	defer cancel()
+
+	res, err := u.cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		Folder:    params.Folder,
+		PublicID:  params.PublicID,
+		Overwrite: &params.Overwrite,
+		Format:    params.Format,
+	})
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, ErrUploadTimeout
+	}
+	if err != nil {
+		// se conserva err para que quien llama distinga, por ejemplo, un error al leer el archivo
+		return nil, fmt.Errorf("%w: %w", ErrUploaderUnavailable, err)
+	}
+	// Cloudinary reporta algunos errores en el cuerpo de la respuesta sin retornar error
+	if res.Error.Message != "" {
+		return nil, fmt.Errorf("cloudinary: %s", res.Error.Message)
+	}
+	// sin overwrite, Cloudinary no falla ante un PublicID en uso: responde con la imagen que ya
+	// existía marcada como "existing" y descarta la subida
+	if !params.Overwrite && existingAsset(res) {
+		return nil, fmt.Errorf("%w: %q", ErrPublicIDTaken, res.PublicID)
+	}
+
+	return &ImageUploadResult{
+		PublicID:  res.PublicID,
+		SecureURL: res.SecureURL,
+		Format:    res.Format,
+	}, nil
+}
+
+// existingAsset indica si la respuesta de Cloudinary corresponde a una imagen que ya existía.
+// El SDK no expone el campo "existing", así que se lee de la respuesta sin procesar.
+func existingAsset(res *uploader.UploadResult) bool {
+	fields, ok := res.Response.(*map[string]interface{})
+	if !ok || fields == nil {
+		return false
+	}
+	existing, _ := (*fields)["existing"].(bool)
+	return existing
+}
+
+func (u *CloudinaryUploader) Destroy(ctx context.Context, publicID string) (err error) {
+	ctx, span := tracing.Start(ctx, "Cloudinary.Destroy", attribute.String("cloudinary.public_id", publicID))
+	defer func() { tracing.End(span, err) }()
+
+	res, err := u.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicID})
+	if err != nil {
+		return err
+	}
+	if res.Error.Message != "" {
+		return fmt.Errorf("cloudinary: %s", res.Error.Message)
+	}
+	return nil
+}
+
+var _ AssetLister = (*CloudinaryUploader)(nil)
+
+// ListCreatedBefore recorre con la Admin API las páginas de recursos cuyo PublicID empieza con
+// folder y retorna los creados antes de before.
+func (u *CloudinaryUploader) ListCreatedBefore(ctx context.Context, folder string, before time.Time) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "Cloudinary.ListCreatedBefore", attribute.String("cloudinary.folder", folder))
+	defer func() { tracing.End(span, err) }()
+
+	var ids []string
+	params := admin.AssetsParams{Prefix: folder + "/", MaxResults: 500}
+	for {
+		res, err := u.cld.Admin.Assets(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUploaderUnavailable, err)
+		}
+		if res.Error.Message != "" {
+			return nil, fmt.Errorf("cloudinary: %s", res.Error.Message)
+		}
+		for _, a := range res.Assets {
+			if a.CreatedAt.Before(before) {
+				ids = append(ids, a.PublicID)
+			}
+		}
+		if res.NextCursor == "" {
+			return ids, nil
+		}
+		params.NextCursor = res.NextCursor
+	}
+}
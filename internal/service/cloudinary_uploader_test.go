@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// untouchedReader falla el test si la subida llega a leer el archivo.
+type untouchedReader struct{ t *testing.T }
+
+func (r untouchedReader) Read(p []byte) (int, error) {
+	r.t.Fatal("se leyó el archivo de una subida a una carpeta no permitida")
+	return 0, nil
+}
+
+func TestUploadRejectsDisallowedFolder(t *testing.T) {
+	// sin cliente de Cloudinary: una carpeta no permitida debe rechazarse antes de usarlo
+	u := NewCloudinaryUploader(nil, time.Second, []string{"posts_images", "previews"})
+
+	for _, folder := range []string{"avatars", "", "posts_images/../avatars", "posts_images2", "Posts_Images"} {
+		t.Run(folder, func(t *testing.T) {
+			res, err := u.Upload(context.Background(), untouchedReader{t}, ImageUploadParams{Folder: folder, PublicID: "post_1"})
+			if !errors.Is(err, ErrFolderNotAllowed) {
+				t.Fatalf("Upload() error = %v, se esperaba ErrFolderNotAllowed", err)
+			}
+			if res != nil {
+				t.Fatalf("Upload() = %+v, se esperaba nil", res)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+)
+
+// PreviewFolder es la carpeta de las imágenes temporales de previsualización.
+const PreviewFolder = "previews"
+
+// ImageUploadParams describe dónde y con qué identificador se guarda una imagen.
+type ImageUploadParams struct {
+	Folder   string
+	PublicID string
+	// Overwrite reemplaza la imagen que ya tenga PublicID. Si es false y el PublicID está en
+	// uso, Upload retorna ErrPublicIDTaken.
+	Overwrite bool
+	// Format convierte la imagen al formato indicado al guardarla (ej. "webp"). Vacío conserva el original.
+	Format string
+}
+
+// ImageUploadResult contiene los datos del recurso subido.
+type ImageUploadResult struct {
+	PublicID  string
+	SecureURL string
+	Format    string
+}
+
+// ImageUploader abstrae el servicio de almacenamiento de imágenes.
+type ImageUploader interface {
+	Upload(ctx context.Context, file io.Reader, params ImageUploadParams) (*ImageUploadResult, error)
+	Destroy(ctx context.Context, publicID string) error
+}
+
+// DiscardUpload elimina una imagen ya subida cuando el paso que debía usarla falló (por
+// ejemplo, guardar la publicación), como compensación para no dejarla huérfana en el
+// almacenamiento. Se ejecuta aunque ctx ya se haya cancelado; si falla solo se registra.
+func DiscardUpload(ctx context.Context, uploader ImageUploader, publicID string) {
+	if publicID == "" {
+		return
+	}
+	if err := uploader.Destroy(context.WithoutCancel(ctx), publicID); err != nil {
+		log.Printf("Error eliminando imagen huérfana %s: %v", publicID, err)
+	}
+}
+
+// NewPublicID genera un PublicID con el prefijo indicado que no se repite entre subidas
+// simultáneas, por ejemplo "post_1718000000123456789_9f2c1a0b".
+func NewPublicID(prefix string) string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s_%d_%s", prefix, time.Now().UnixNano(), hex.EncodeToString(b))
+}
+
+// URLWithFormat retorna la URL de entrega del recurso en otro formato. Cloudinary convierte
+// al vuelo según la extensión solicitada.
+func URLWithFormat(url, format string) string {
+	ext := path.Ext(url)
+	if ext == "" {
+		return url + "." + format
+	}
+	return strings.TrimSuffix(url, ext) + "." + format
+}
+
+// TransformedURL inserta una transformación de Cloudinary (ej. "c_thumb,w_300,h_300") en la
+// URL de entrega del recurso.
+func TransformedURL(url, transformation string) string {
+	const marker = "/upload/"
+	i := strings.Index(url, marker)
+	if i < 0 {
+		return url
+	}
+	return url[:i+len(marker)] + transformation + "/" + url[i+len(marker):]
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+// recordingUploader registra las imágenes eliminadas y el contexto con el que se pidió.
+type recordingUploader struct {
+	destroyed []string
+	ctxErr    error
+}
+
+func (u *recordingUploader) Upload(ctx context.Context, file io.Reader, params ImageUploadParams) (*ImageUploadResult, error) {
+	return nil, nil
+}
+
+func (u *recordingUploader) Destroy(ctx context.Context, publicID string) error {
+	u.destroyed = append(u.destroyed, publicID)
+	u.ctxErr = ctx.Err()
+	return nil
+}
+
+func TestDiscardUploadDestroysOnceEvenIfCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	u := &recordingUploader{}
+	DiscardUpload(ctx, u, "posts_images/post_1")
+
+	if len(u.destroyed) != 1 || u.destroyed[0] != "posts_images/post_1" {
+		t.Fatalf("imágenes eliminadas = %v", u.destroyed)
+	}
+	if u.ctxErr != nil {
+		t.Fatalf("Destroy recibió un contexto cancelado: %v", u.ctxErr)
+	}
+}
+
+func TestDiscardUploadWithoutPublicIDDoesNothing(t *testing.T) {
+	u := &recordingUploader{}
+	DiscardUpload(context.Background(), u, "")
+
+	if len(u.destroyed) != 0 {
+		t.Fatalf("imágenes eliminadas = %v, se esperaba ninguna", u.destroyed)
+	}
+}
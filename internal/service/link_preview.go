@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"golang.org/x/net/html"
+)
+
+// maxPreviewBytes es cuánto del documento se lee buscando las etiquetas Open Graph, que
+// suelen estar al principio, en el <head>.
+const maxPreviewBytes = 512 << 10
+
+// errPrivateAddress indica que el enlace apunta a una dirección de la red interna.
+var errPrivateAddress = errors.New("la dirección del enlace no es pública")
+
+// LinkPreviewFetcher obtiene los metadatos Open Graph de páginas externas. Solo se conecta a
+// direcciones públicas, para que un enlace en una publicación no sirva para alcanzar servicios
+// de la red interna.
+type LinkPreviewFetcher struct {
+	client *http.Client
+}
+
+func NewLinkPreviewFetcher(timeout time.Duration) *LinkPreviewFetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &LinkPreviewFetcher{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+	}
+}
+
+// Fetch descarga la página y retorna su vista previa. Retorna error si la página no responde,
+// no es HTML o no tiene título.
+func (f *LinkPreviewFetcher) Fetch(ctx context.Context, url string) (*models.LinkPreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("la página respondió %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, fmt.Errorf("la página no es HTML: %s", resp.Header.Get("Content-Type"))
+	}
+
+	preview := parseOpenGraph(io.LimitReader(resp.Body, maxPreviewBytes))
+	if preview.Title == "" {
+		return nil, errors.New("la página no tiene título")
+	}
+	preview.URL = url
+	return preview, nil
+}
+
+// parseOpenGraph lee las etiquetas og:title, og:description y og:image, usando <title> si la
+// página no declara og:title.
+func parseOpenGraph(r io.Reader) *models.LinkPreview {
+	preview := &models.LinkPreview{}
+	var fallbackTitle string
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if preview.Title == "" {
+				preview.Title = fallbackTitle
+			}
+			return preview
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				if z.Next() == html.TextToken {
+					fallbackTitle = strings.TrimSpace(z.Token().Data)
+				}
+			case "meta":
+				var property, content string
+				for _, a := range tok.Attr {
+					switch a.Key {
+					case "property", "name":
+						property = a.Val
+					case "content":
+						content = strings.TrimSpace(a.Val)
+					}
+				}
+				switch property {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image":
+					preview.ImageURL = content
+				}
+			}
+		case html.EndTagToken:
+			// todo lo necesario está en el <head>
+			if z.Token().Data == "head" {
+				if preview.Title == "" {
+					preview.Title = fallbackTitle
+				}
+				return preview
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MentionWebhook notifica a un servicio externo cada vez que un usuario es mencionado.
+// Si no se configura una URL, las notificaciones se ignoran.
+type MentionWebhook struct {
+	url    string
+	client *http.Client
+}
+
+func NewMentionWebhook(url string) *MentionWebhook {
+	return &MentionWebhook{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (n *MentionWebhook) NotifyMention(ctx context.Context, userID, postID string) error {
+	if n.url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"type":    "mention",
+		"user_id": userID,
+		"post_id": postID,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook de menciones respondió %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Package tracing configura el envío de trazas OpenTelemetry por OTLP y ofrece ayudas para
+// crear spans. Mientras Setup no se llame con el trazado activado, los spans no hacen nada.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JuanPidarraga/talkus-backend/config"
+)
+
+// Atributos comunes de los spans.
+const (
+	PostIDKey = attribute.Key("post.id")
+	UserIDKey = attribute.Key("user.id")
+)
+
+const tracerName = "github.com/JuanPidarraga/talkus-backend"
+
+// Setup registra el proveedor global de trazas que exporta a cfg.Endpoint. Retorna la función
+// que envía los spans pendientes al apagar el servidor. Si el trazado está desactivado no
+// registra nada y la función retornada no hace nada.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Start inicia un span hijo del que lleve ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End cierra el span registrando err, si lo hay.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetUser agrega el usuario autenticado al span de la petición en curso.
+func SetUser(ctx context.Context, uid string) {
+	trace.SpanFromContext(ctx).SetAttributes(UserIDKey.String(uid))
+}
+
+// RouteNames es un middleware de mux que nombra el span de la petición con su método y la
+// plantilla de la ruta (por ejemplo "GET /public/posts/{id}"), en lugar de la URL concreta.
+func RouteNames(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if tmpl, err := route.GetPathTemplate(); err == nil {
+				trace.SpanFromContext(r.Context()).SetName(r.Method + " " + tmpl)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
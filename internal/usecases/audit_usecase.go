@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+type AuditUsecase struct {
+	repo *repositories.AuditLogRepository
+}
+
+func NewAuditUsecase(repo *repositories.AuditLogRepository) *AuditUsecase {
+	return &AuditUsecase{repo: repo}
+}
+
+// Record guarda una acción de moderación. La escritura es best-effort: si falla se registra
+// en el log del servidor pero no interrumpe la operación que la originó.
+func (u *AuditUsecase) Record(ctx context.Context, actorID, action, targetID, reason string) {
+	entry := &models.AuditLog{
+		ActorID:   actorID,
+		Action:    action,
+		TargetID:  targetID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if err := u.repo.Create(ctx, entry); err != nil {
+		log.Printf("Error guardando log de auditoría (actor=%s action=%s target=%s): %v",
+			actorID, action, targetID, err)
+	}
+}
+
+// List retorna la página de entradas del log de auditoría que cumplen el filtro, junto con el
+// total de entradas que lo cumplen.
+func (u *AuditUsecase) List(ctx context.Context, f models.AuditLogFilter) ([]*models.AuditLog, int64, error) {
+	entries, err := u.repo.List(ctx, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := u.repo.Count(ctx, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
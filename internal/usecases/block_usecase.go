@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/cache"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+// blockCacheTTL define cuánto tiempo se reutilizan los bloqueos de un usuario, que se consultan
+// en cada listado e interacción. Los cambios hechos en esta instancia se ven en el acto.
+const blockCacheTTL = 30 * time.Second
+
+// BlockUsecase gestiona los bloqueos entre usuarios. A diferencia de silenciar, un bloqueo
+// afecta a los dos: ninguno ve las publicaciones del otro y el bloqueado no puede interactuar
+// con las publicaciones de quien lo bloqueó. Las interacciones previas no se eliminan.
+type BlockUsecase struct {
+	repo     *repositories.BlockRepository
+	userRepo *repositories.UserRepository
+	related  *cache.TTLCache[[]string]
+}
+
+func NewBlockUsecase(repo *repositories.BlockRepository, userRepo *repositories.UserRepository) *BlockUsecase {
+	return &BlockUsecase{repo: repo, userRepo: userRepo, related: cache.NewTTLCache[[]string](blockCacheTTL)}
+}
+
+// Block bloquea a blockedID en nombre de blockerID. Bloquear de nuevo no cambia nada.
+// Retorna ErrSelfBlock si son el mismo usuario y ErrUserNotFound si blockedID no existe.
+func (u *BlockUsecase) Block(ctx context.Context, blockerID, blockedID string) error {
+	if blockerID == blockedID {
+		return ErrSelfBlock
+	}
+	if _, err := u.userRepo.GetUserByID(ctx, blockedID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	err := u.repo.Create(ctx, &models.Block{BlockerID: blockerID, BlockedID: blockedID, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	u.related.Delete(blockerID)
+	u.related.Delete(blockedID)
+	return nil
+}
+
+// Unblock quita el bloqueo de blockerID a blockedID. No falla si no existía; si blockedID
+// también bloqueó a blockerID, ese bloqueo se mantiene.
+func (u *BlockUsecase) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	if err := u.repo.Delete(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+	u.related.Delete(blockerID)
+	u.related.Delete(blockedID)
+	return nil
+}
+
+// Hidden retorna los usuarios cuyas publicaciones no debe ver userID: los que bloqueó y los que
+// lo bloquearon. Sin userID (visitante anónimo) no oculta a nadie.
+func (u *BlockUsecase) Hidden(ctx context.Context, userID string) ([]string, error) {
+	if userID == "" {
+		return nil, nil
+	}
+	if ids, ok := u.related.Get(userID); ok {
+		return ids, nil
+	}
+	ids, err := u.repo.GetRelated(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	u.related.Set(userID, ids)
+	return ids, nil
+}
+
+// CheckInteraction retorna ErrBlocked si hay un bloqueo, en cualquier sentido, entre userID y
+// authorID, el autor de la publicación con la que quiere interactuar.
+func (u *BlockUsecase) CheckInteraction(ctx context.Context, userID, authorID string) error {
+	if userID == "" || authorID == "" || userID == authorID {
+		return nil
+	}
+	hidden, err := u.Hidden(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(hidden, authorID) {
+		return ErrBlocked
+	}
+	return nil
+}
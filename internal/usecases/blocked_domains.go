@@ -0,0 +1,128 @@
+package usecases
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Modos de reacción ante un enlace a un dominio bloqueado.
+const (
+	// BlockedDomainsReject rechaza la publicación con ErrBlockedDomain.
+	BlockedDomainsReject = "reject"
+	// BlockedDomainsFlag guarda la publicación marcada como reportada para que la revise un moderador.
+	BlockedDomainsFlag = "flag"
+)
+
+// hostPattern reconoce nombres de dominio en el texto, tengan o no esquema, ruta o usuario
+// delante ("http://x@spam.com").
+var hostPattern = regexp.MustCompile(`(?i)(?:[\p{L}\p{N}](?:[\p{L}\p{N}-]*[\p{L}\p{N}])?\.)+[\p{L}][\p{L}\p{N}-]*[\p{L}\p{N}]`)
+
+// dotReplacer deshace los disfraces habituales del punto en los enlaces de spam, como
+// "spam[.]com" o "spam(dot)com", y quita los caracteres invisibles que parten un dominio.
+var dotReplacer = strings.NewReplacer(
+	"[.]", ".", "(.)", ".", "{.}", ".",
+	"[dot]", ".", "(dot)", ".", "{dot}", ".",
+	"\u3002", ".", "\uff0e", ".", "\uff61", ".",
+	"\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "",
+)
+
+// DomainBlocklist detecta enlaces a dominios bloqueados. Un dominio bloquea también todos sus
+// subdominios. El valor nil no bloquea nada.
+type DomainBlocklist struct {
+	domains map[string]bool
+	mode    string
+}
+
+// NewDomainBlocklist crea la lista a partir de los dominios dados, que pueden incluir esquema o
+// ruta ("https://spam.com/x") y se comparan sin distinguir mayúsculas. mode es
+// BlockedDomainsReject o BlockedDomainsFlag.
+func NewDomainBlocklist(domains []string, mode string) *DomainBlocklist {
+	b := &DomainBlocklist{domains: make(map[string]bool, len(domains)), mode: mode}
+	for _, d := range domains {
+		for _, host := range hostPattern.FindAllString(dotReplacer.Replace(strings.ToLower(d)), 1) {
+			if host = normalizeHost(host); host != "" {
+				b.domains[host] = true
+			}
+		}
+	}
+	return b
+}
+
+// Len retorna cuántos dominios están bloqueados.
+func (b *DomainBlocklist) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.domains)
+}
+
+// Find retorna el primer dominio bloqueado al que enlaza el texto, o "" si no hay ninguno.
+func (b *DomainBlocklist) Find(text string) string {
+	if b.Len() == 0 {
+		return ""
+	}
+
+	text = dotReplacer.Replace(strings.ToLower(text))
+	for _, host := range hostPattern.FindAllString(text, -1) {
+		if domain := b.match(normalizeHost(host)); domain != "" {
+			return domain
+		}
+	}
+	return ""
+}
+
+// match busca host y cada dominio padre hasta el dominio registrable (ej. para
+// "a.b.spam.co.uk" prueba hasta "spam.co.uk"), retornando el que esté bloqueado.
+func (b *DomainBlocklist) match(host string) string {
+	if host == "" {
+		return ""
+	}
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		registrable = host
+	}
+	for {
+		if b.domains[host] {
+			return host
+		}
+		if host == registrable {
+			return ""
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			return ""
+		}
+		host = host[i+1:]
+	}
+}
+
+// normalizeHost pasa el dominio a minúsculas y a su forma ASCII (punycode), para que un
+// dominio con caracteres internacionales coincida con su versión "xn--". Retorna "" si no es
+// un dominio válido.
+func normalizeHost(host string) string {
+	host, err := idna.Lookup.ToASCII(strings.Trim(strings.ToLower(host), ".-"))
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// checkBlockedDomains aplica la lista de dominios bloqueados al título y al contenido. En modo
+// BlockedDomainsFlag marca la publicación como reportada; en otro caso retorna ErrBlockedDomain.
+func (u *PostUsecase) checkBlockedDomains(p *models.Post) error {
+	domain := u.blocked.Find(p.Title + "\n" + p.Content)
+	if domain == "" {
+		return nil
+	}
+	if u.blocked.mode == BlockedDomainsFlag {
+		log.Printf("Publicación de %s marcada por enlazar a %s", p.AuthorID, domain)
+		p.IsFlagged = true
+		return nil
+	}
+	return ErrBlockedDomain
+}
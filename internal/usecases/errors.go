@@ -0,0 +1,40 @@
+package usecases
+
+import "errors"
+
+var (
+	// ErrUserNotFound indica que el usuario solicitado no existe.
+	ErrUserNotFound = errors.New("usuario no encontrado")
+	// ErrPostNotFound indica que la publicación solicitada no existe o fue eliminada.
+	ErrPostNotFound = errors.New("publicación no encontrada")
+	// ErrNotPostAuthor indica que solo el autor de la publicación, o un moderador, puede modificarla.
+	ErrNotPostAuthor = errors.New("solo el autor de la publicación puede modificarla")
+	// ErrInvalidTags indica que la lista de etiquetas recibida no es válida.
+	ErrInvalidTags = errors.New("etiquetas inválidas")
+	// ErrInvalidReaction indica un tipo de reacción desconocido.
+	ErrInvalidReaction = errors.New("la reacción debe ser like, dislike, love, laugh, angry o sad")
+	// ErrInvalidSlug indica un slug con caracteres no permitidos o demasiado largo.
+	ErrInvalidSlug = errors.New("el slug solo puede tener letras, dígitos y guiones, con un máximo de 80 caracteres")
+	// ErrSlugReserved indica un slug reservado para rutas del sitio.
+	ErrSlugReserved = errors.New("el slug está reservado")
+	// ErrSlugTaken indica que otra publicación ya usa el slug.
+	ErrSlugTaken = errors.New("el slug ya está en uso")
+	// ErrDailyPostLimit indica que el usuario alcanzó el máximo de publicaciones en 24 horas.
+	ErrDailyPostLimit = errors.New("alcanzaste el máximo de publicaciones por día, intenta más tarde")
+	// ErrBlockedDomain indica que la publicación enlaza a un dominio bloqueado.
+	ErrBlockedDomain = errors.New("la publicación enlaza a un dominio no permitido")
+	// ErrSameUser indica que el usuario de origen y de destino de una transferencia coinciden.
+	ErrSameUser = errors.New("el usuario de destino debe ser distinto del de origen")
+	// ErrMergeIntoSelf indica que la publicación principal de una fusión está entre los duplicados.
+	ErrMergeIntoSelf = errors.New("la publicación principal no puede estar entre los duplicados")
+	// ErrMergeTooLarge indica que la fusión mueve demasiadas reacciones o reposts para hacerse de una vez.
+	ErrMergeTooLarge = errors.New("los duplicados tienen demasiadas interacciones para fusionarlos juntos; fusiónalos en grupos más pequeños")
+	// ErrSelfMute indica que un usuario intentó silenciarse a sí mismo.
+	ErrSelfMute = errors.New("no puedes silenciarte a ti mismo")
+	// ErrSelfBlock indica que un usuario intentó bloquearse a sí mismo.
+	ErrSelfBlock = errors.New("no puedes bloquearte a ti mismo")
+	// ErrBlocked indica que un bloqueo entre los usuarios impide la interacción.
+	ErrBlocked = errors.New("no puedes interactuar con las publicaciones de este usuario")
+	// ErrTooManyMuted indica que el usuario alcanzó el máximo de usuarios silenciados.
+	ErrTooManyMuted = errors.New("alcanzaste el máximo de usuarios silenciados")
+)
@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"strings"
+	"unicode"
+)
+
+// markdownSyntax son los caracteres de Markdown que no se quitan del final del resumen, porque
+// pueden cerrar un énfasis, un código o un enlace.
+const markdownSyntax = "*_~`)]"
+
+// Excerpt retorna un resumen de text de hasta maxRunes caracteres, cortando en el último
+// espacio para no partir palabras y agregando "…" cuando el texto se recorta.
+// Si el corte deja abierto un énfasis o un código de Markdown, se cierra; si cae dentro de un
+// enlace, se corta antes del enlace. Los cierres agregados no cuentan en maxRunes.
+func Excerpt(text string, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	cut := runes[:maxRunes]
+	// si el corte cae justo antes de un espacio no parte ninguna palabra
+	if i := lastSpace(cut); i > 0 && !unicode.IsSpace(runes[maxRunes]) {
+		cut = cut[:i]
+	}
+	cut, closers := balanceMarkdown(trimExcerptEnd(cut))
+	return string(cut) + closers + "…"
+}
+
+func lastSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// trimExcerptEnd quita los espacios y la puntuación del final, salvo la sintaxis de Markdown.
+func trimExcerptEnd(runes []rune) []rune {
+	end := len(runes)
+	for end > 0 {
+		r := runes[end-1]
+		if !unicode.IsSpace(r) && (!unicode.IsPunct(r) || strings.ContainsRune(markdownSyntax, r)) {
+			break
+		}
+		end--
+	}
+	return runes[:end]
+}
+
+// balanceMarkdown recorre el texto recortado y retorna la parte que se puede mostrar junto con
+// los delimitadores que faltan para cerrar los énfasis y el código que quedaron abiertos. Un
+// enlace o imagen sin terminar se quita entero; si el texto empieza con él, se deja solo su texto.
+func balanceMarkdown(runes []rune) ([]rune, string) {
+	var open []string
+	code := ""
+	linkStart, textStart := -1, -1
+	inURL := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if code != "" {
+			// dentro de un código solo importa la secuencia de comillas que lo cierra
+			if r == '`' {
+				n := runLength(runes, i)
+				if n == len(code) {
+					code = ""
+				}
+				i += n - 1
+			}
+			continue
+		}
+
+		switch {
+		case r == '\\':
+			i++
+		case r == '`':
+			n := runLength(runes, i)
+			code = string(runes[i : i+n])
+			i += n - 1
+		case r == '[' && linkStart < 0:
+			linkStart, textStart = i, i+1
+			if i > 0 && runes[i-1] == '!' {
+				linkStart--
+			}
+		case r == ']' && linkStart >= 0 && !inURL:
+			if i+1 < len(runes) && runes[i+1] == '(' {
+				inURL = true
+				i++
+			} else {
+				linkStart = -1
+			}
+		case r == ')' && inURL:
+			linkStart, inURL = -1, false
+		case (r == '*' || r == '_' || r == '~') && !inURL:
+			n := runLength(runes, i)
+			delim := string(runes[i : i+n])
+			if isEmphasisDelimiter(runes, i, n) {
+				if len(open) > 0 && open[len(open)-1] == delim {
+					open = open[:len(open)-1]
+				} else {
+					open = append(open, delim)
+				}
+			}
+			i += n - 1
+		}
+	}
+
+	if linkStart >= 0 {
+		if head := trimExcerptEnd(runes[:linkStart]); len(head) > 0 {
+			return balanceMarkdown(head)
+		}
+		text := runes[textStart:]
+		for i, r := range text {
+			if r == ']' {
+				text = text[:i]
+				break
+			}
+		}
+		return balanceMarkdown(trimExcerptEnd(text))
+	}
+
+	closers := code
+	if code != "" && runes[len(runes)-1] == '`' {
+		// pegadas a una comilla del contenido formarían una secuencia más larga que no cierra
+		closers = " " + code
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		closers += open[i]
+	}
+	return runes, closers
+}
+
+// runLength retorna cuántas veces seguidas se repite runes[i] desde i.
+func runLength(runes []rune, i int) int {
+	n := 1
+	for i+n < len(runes) && runes[i+n] == runes[i] {
+		n++
+	}
+	return n
+}
+
+// isEmphasisDelimiter indica si la secuencia de n delimitadores en i abre o cierra un énfasis:
+// no lo hace si está rodeada de espacios ("2 * 3"), si es un "_" dentro de una palabra
+// (snake_case) ni si es un "~" suelto.
+func isEmphasisDelimiter(runes []rune, i, n int) bool {
+	if runes[i] == '~' && n < 2 {
+		return false
+	}
+	before, after := ' ', ' '
+	if i > 0 {
+		before = runes[i-1]
+	}
+	if i+n < len(runes) {
+		after = runes[i+n]
+	}
+	if unicode.IsSpace(before) && unicode.IsSpace(after) {
+		return false
+	}
+	if runes[i] == '_' && isWordRune(before) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import "testing"
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{"texto corto", "hola mundo", 20, "hola mundo"},
+		{"corta en el último espacio", "una frase bastante larga para cortar", 15, "una frase…"},
+		{"corte justo en un espacio", "texto **negrita** y más cosas", 17, "texto **negrita**…"},
+		{"quita la puntuación final", "palabra, otra palabra; y más", 14, "palabra, otra…"},
+
+		{"cierra la negrita abierta", "texto con **negrita que sigue** después", 22, "texto con **negrita**…"},
+		{"cierra la negrita con guiones bajos", "texto con __negrita que sigue__ después", 22, "texto con __negrita__…"},
+		{"cierra énfasis anidados en orden", "*cursiva y **negrita anidada** juntas* fin", 25, "*cursiva y **negrita***…"},
+		{"negrita desde el inicio", "**todo en negrita hasta el final del texto**", 20, "**todo en negrita**…"},
+		{"negrita completa antes del corte", "texto **negrita** y más cosas", 18, "texto **negrita**…"},
+		{"cierra el tachado", "un ~~tachado largo que sigue~~ aquí", 20, "un ~~tachado largo~~…"},
+		{"asteriscos entre espacios no son énfasis", "calcula 2 * 3 y luego 4 * 5 para terminar", 27, "calcula 2 * 3 y luego 4 * 5…"},
+		{"guiones bajos dentro de palabras", "usa snake_case_names en Go no tanto", 22, "usa snake_case_names…"},
+		{"asterisco escapado", "escapa \\*esto no es énfasis y sigue", 22, "escapa \\*esto no es…"},
+
+		{"cierra el código abierto", "el comando `go test ./...` corre todo", 18, "el comando `go`…"},
+		{"el énfasis dentro del código no se cierra", "así se escribe `**no es negrita**` en código", 28, "así se escribe `**no es`…"},
+		{"código con comillas dobles", "usa ``código con ` dentro`` y más", 20, "usa ``código con ` ``…"},
+
+		{"quita el enlace cortado", "lee [la documentación](https://go.dev/doc) completa", 20, "lee…"},
+		{"quita el enlace cortado en la URL", "lee [la guía](https://go.dev/doc/tutorial) hoy", 30, "lee…"},
+		{"conserva el enlace completo", "ver [docs](https://go.dev) y luego seguir leyendo", 30, "ver [docs](https://go.dev) y…"},
+		{"enlace al inicio deja su texto", "[enlace largo al principio](https://go.dev) y más texto", 12, "enlace…"},
+		{"quita la imagen cortada", "mira ![imagen](https://a.test/i.png) aquí", 18, "mira…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Excerpt(tt.text, tt.maxRunes); got != tt.want {
+				t.Errorf("Excerpt(%q, %d) = %q, se esperaba %q", tt.text, tt.maxRunes, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// maxPendingImages es el número máximo de imágenes en espera de subirse. Cada una se mantiene
+// completa en memoria hasta que se sube.
+const maxPendingImages = 100
+
+// maxImageAttempts es cuántas veces se reintenta subir una imagen antes de descartarla.
+const maxImageAttempts = 10
+
+// ErrRetryQueueFull indica que no caben más imágenes pendientes en la cola de reintentos.
+var ErrRetryQueueFull = errors.New("la cola de imágenes pendientes está llena")
+
+// errImageRejected indica que Cloudinary rechazó la imagen, así que reintentar no sirve.
+var errImageRejected = errors.New("el servicio de imágenes rechazó la imagen")
+
+// PendingImageStore guarda en la publicación el resultado de reintentar su imagen.
+type PendingImageStore interface {
+	UpdateImage(ctx context.Context, id, url, fallbackURL, publicID string) error
+	ClearImagePending(ctx context.Context, id string) error
+}
+
+var _ PendingImageStore = (*repositories.PostRepository)(nil)
+
+// pendingImage es una imagen que no se pudo subir al crear su publicación.
+type pendingImage struct {
+	postID   string
+	data     []byte
+	params   service.ImageUploadParams
+	attempts int
+}
+
+// ImageRetrier reintenta en segundo plano las subidas de imágenes que fallaron porque
+// Cloudinary no estaba disponible, y completa la publicación cuando alguna lo logra.
+//
+// La cola vive en memoria de la instancia: si el proceso se reinicia, las imágenes pendientes
+// se pierden y sus publicaciones quedan con ImagePending en true y sin imagen. Las imágenes que
+// Cloudinary rechaza, o que agotan maxImageAttempts, se descartan y su publicación queda sin
+// imagen y sin ImagePending, para que no ocupen la cola para siempre.
+type ImageRetrier struct {
+	postRepo PendingImageStore
+	uploader service.ImageUploader
+
+	mu      sync.Mutex
+	pending []*pendingImage
+}
+
+func NewImageRetrier(postRepo PendingImageStore, uploader service.ImageUploader) *ImageRetrier {
+	return &ImageRetrier{postRepo: postRepo, uploader: uploader}
+}
+
+// Enqueue agrega la imagen de la publicación a la cola de reintentos.
+func (q *ImageRetrier) Enqueue(postID string, data []byte, params service.ImageUploadParams) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) >= maxPendingImages {
+		return ErrRetryQueueFull
+	}
+	q.pending = append(q.pending, &pendingImage{postID: postID, data: data, params: params})
+	return nil
+}
+
+// Reserve indica si hay espacio para una imagen más en la cola. Permite rechazar la
+// publicación antes de crearla cuando la cola ya está llena.
+func (q *ImageRetrier) Reserve() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) < maxPendingImages
+}
+
+// Retry intenta subir todas las imágenes pendientes y retorna cuántas se completaron. Las que
+// vuelven a fallar por un error transitorio se conservan para el siguiente intento, hasta
+// maxImageAttempts; las demás se descartan.
+func (q *ImageRetrier) Retry(ctx context.Context) int {
+	q.mu.Lock()
+	batch := q.pending
+	q.pending = nil
+	q.mu.Unlock()
+
+	var failed []*pendingImage
+	done := 0
+	for _, img := range batch {
+		if err := q.upload(ctx, img); err != nil {
+			if errors.Is(err, service.ErrPublicIDTaken) {
+				// un intento anterior pudo haber llegado a Cloudinary aunque se reportara como
+				// fallido; se sube con otro identificador para no tomar una imagen ajena
+				img.params.PublicID = service.NewPublicID("post")
+			}
+			img.attempts++
+			log.Printf("Reintento %d de la imagen de %s falló: %v", img.attempts, img.postID, err)
+			if !retryableUpload(err) || img.attempts >= maxImageAttempts {
+				q.giveUp(ctx, img)
+				continue
+			}
+			failed = append(failed, img)
+			continue
+		}
+		done++
+	}
+
+	if len(failed) > 0 {
+		q.mu.Lock()
+		q.pending = append(failed, q.pending...)
+		q.mu.Unlock()
+	}
+	return done
+}
+
+// retryableUpload indica si vale la pena volver a intentar una subida que falló con err. Los
+// errores al guardar la imagen en la publicación también se reintentan.
+func retryableUpload(err error) bool {
+	return !errors.Is(err, errImageRejected)
+}
+
+// giveUp descarta la imagen y quita la marca de pendiente de su publicación.
+func (q *ImageRetrier) giveUp(ctx context.Context, img *pendingImage) {
+	log.Printf("Se descarta la imagen pendiente de %s tras %d intentos", img.postID, img.attempts)
+	err := q.postRepo.ClearImagePending(ctx, img.postID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		log.Printf("No se pudo quitar la imagen pendiente de %s: %v", img.postID, err)
+	}
+}
+
+func (q *ImageRetrier) upload(ctx context.Context, img *pendingImage) error {
+	res, err := q.uploader.Upload(ctx, bytes.NewReader(img.data), img.params)
+	if err != nil {
+		if errors.Is(err, service.ErrUploadTimeout) || errors.Is(err, service.ErrUploaderUnavailable) ||
+			errors.Is(err, service.ErrPublicIDTaken) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", errImageRejected, err)
+	}
+
+	var fallbackURL string
+	if res.Format == "webp" {
+		fallbackURL = service.URLWithFormat(res.SecureURL, "jpg")
+	}
+	err = q.postRepo.UpdateImage(ctx, img.postID, res.SecureURL, fallbackURL, res.PublicID)
+	if err == nil {
+		return nil
+	}
+	// la imagen no quedó asociada: se elimina para que el siguiente intento no deje otra huérfana
+	service.DiscardUpload(ctx, q.uploader, res.PublicID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// la publicación se eliminó mientras esperaba; la imagen ya no se necesita
+		return nil
+	}
+	return err
+}
+
+// Run ejecuta Retry cada interval hasta que ctx se cancela.
+func (q *ImageRetrier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if done := q.Retry(ctx); done > 0 {
+				log.Printf("%d imágenes pendientes subidas", done)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
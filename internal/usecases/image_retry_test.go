@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// failingUploader falla todas las subidas con err.
+type failingUploader struct {
+	err     error
+	uploads int
+}
+
+func (u *failingUploader) Upload(ctx context.Context, file io.Reader, params service.ImageUploadParams) (*service.ImageUploadResult, error) {
+	u.uploads++
+	return nil, u.err
+}
+
+func (u *failingUploader) Destroy(ctx context.Context, publicID string) error { return nil }
+
+// pendingStore registra las publicaciones cuya imagen dejó de estar pendiente sin guardarse.
+type pendingStore struct {
+	cleared []string
+}
+
+func (s *pendingStore) UpdateImage(ctx context.Context, id, url, fallbackURL, publicID string) error {
+	return errors.New("no se esperaban imágenes guardadas")
+}
+
+func (s *pendingStore) ClearImagePending(ctx context.Context, id string) error {
+	s.cleared = append(s.cleared, id)
+	return nil
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	store := &pendingStore{}
+	uploader := &failingUploader{err: service.ErrUploaderUnavailable}
+	q := NewImageRetrier(store, uploader)
+	for i := range maxPendingImages {
+		if err := q.Enqueue(fmt.Sprintf("p%d", i), []byte("imagen"), service.ImageUploadParams{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if q.Reserve() {
+		t.Fatal("Reserve con la cola llena = true")
+	}
+
+	for range maxImageAttempts - 1 {
+		q.Retry(context.Background())
+	}
+	if len(store.cleared) != 0 {
+		t.Fatalf("se descartaron %d imágenes antes de agotar los intentos", len(store.cleared))
+	}
+	q.Retry(context.Background())
+
+	if len(store.cleared) != maxPendingImages {
+		t.Fatalf("se descartaron %d imágenes, se esperaban %d", len(store.cleared), maxPendingImages)
+	}
+	if !q.Reserve() {
+		t.Fatal("las imágenes descartadas siguen ocupando la cola")
+	}
+	if uploader.uploads != maxPendingImages*maxImageAttempts {
+		t.Fatalf("subidas = %d, se esperaban %d", uploader.uploads, maxPendingImages*maxImageAttempts)
+	}
+}
+
+func TestRetryDropsRejectedImages(t *testing.T) {
+	store := &pendingStore{}
+	q := NewImageRetrier(store, &failingUploader{err: errors.New("formato no soportado")})
+	if err := q.Enqueue("p1", []byte("imagen"), service.ImageUploadParams{}); err != nil {
+		t.Fatal(err)
+	}
+
+	q.Retry(context.Background())
+
+	if len(store.cleared) != 1 || store.cleared[0] != "p1" {
+		t.Fatalf("publicaciones sin imagen pendiente = %v, se esperaba [p1]", store.cleared)
+	}
+	if len(q.pending) != 0 {
+		t.Fatalf("quedaron %d imágenes en la cola", len(q.pending))
+	}
+}
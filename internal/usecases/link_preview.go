@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// LinkPreviewer obtiene la vista previa de un enlace externo.
+type LinkPreviewer interface {
+	Fetch(ctx context.Context, url string) (*models.LinkPreview, error)
+}
+
+// linkPreviewTimeout limita el tiempo total de obtener y guardar una vista previa.
+const linkPreviewTimeout = 15 * time.Second
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// FirstURL retorna el primer enlace http(s) del texto, o "" si no tiene.
+func FirstURL(text string) string {
+	return urlPattern.FindString(text)
+}
+
+// attachLinkPreview obtiene en segundo plano la vista previa del primer enlace del contenido y
+// la guarda en la publicación. Si falla, la publicación queda sin vista previa.
+func (u *PostUsecase) attachLinkPreview(p *models.Post) {
+	url := FirstURL(p.Content)
+	if url == "" {
+		return
+	}
+
+	go func(postID string) {
+		ctx, cancel := context.WithTimeout(context.Background(), linkPreviewTimeout)
+		defer cancel()
+
+		preview, err := u.previews.Fetch(ctx, url)
+		if err != nil {
+			log.Printf("Sin vista previa para %s: %v", url, err)
+			return
+		}
+		if err := u.repo.UpdateLinkPreview(ctx, postID, preview); err != nil {
+			log.Printf("Error guardando vista previa de %s: %v", postID, err)
+			return
+		}
+		u.postsChanged()
+	}(p.ID)
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import "regexp"
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.]{1,30})`)
+
+// maxMentions es cuántos usuarios distintos se mencionan como máximo en una publicación; las
+// menciones siguientes quedan como texto.
+const maxMentions = 20
+
+// ParseMentions extrae los nombres de usuario mencionados con @ en el texto, sin duplicados
+// y en el orden en que aparecen, hasta maxMentions.
+func ParseMentions(text string) []string {
+	matches := mentionPattern.FindAllStringSubmatch(text, -1)
+	seen := make(map[string]bool, len(matches))
+	usernames := make([]string, 0, len(matches))
+
+	for _, m := range matches {
+		username := m[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+	return usernames
+}
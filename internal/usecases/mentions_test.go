@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
+)
+
+func TestParseMentionsCapsDistinctUsers(t *testing.T) {
+	var text strings.Builder
+	for i := range maxMentions + 5 {
+		fmt.Fprintf(&text, "@usuario%d @usuario%d ", i, i)
+	}
+
+	got := ParseMentions(text.String())
+	if len(got) != maxMentions {
+		t.Fatalf("ParseMentions retornó %d menciones, se esperaban %d", len(got), maxMentions)
+	}
+	if got[0] != "usuario0" || got[maxMentions-1] != fmt.Sprintf("usuario%d", maxMentions-1) {
+		t.Fatalf("ParseMentions = %q, se esperaban las primeras %d en orden", got, maxMentions)
+	}
+}
+
+// slowNotifier registra cuántos avisos se envían a la vez.
+type slowNotifier struct {
+	active, peak atomic.Int32
+	wg           sync.WaitGroup
+}
+
+func (n *slowNotifier) NotifyMention(ctx context.Context, userID, postID string) error {
+	defer n.wg.Done()
+	active := n.active.Add(1)
+	defer n.active.Add(-1)
+	for {
+		peak := n.peak.Load()
+		if active <= peak || n.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return nil
+}
+
+func TestNotifyMentionsBoundsConcurrency(t *testing.T) {
+	notifier := &slowNotifier{}
+	u := NewPostUsecase(nil, nil, PostUsecaseOptions{Notifier: notifier})
+
+	userIDs := make([]string, maxMentions)
+	for i := range userIDs {
+		userIDs[i] = fmt.Sprintf("u%d", i)
+	}
+	notifier.wg.Add(2 * len(userIDs))
+	u.notifyMentions("p1", userIDs)
+	u.notifyMentions("p2", userIDs)
+	notifier.wg.Wait()
+
+	if peak := notifier.peak.Load(); peak > mentionWorkers {
+		t.Fatalf("se enviaron %d avisos a la vez, el máximo es %d", peak, mentionWorkers)
+	}
+}
+
+// defaultFlags deja todas las funcionalidades con su valor por defecto.
+type defaultFlags struct{}
+
+func (defaultFlags) Lookup(string) (bool, bool) { return false, false }
+
+// usernameIDs resuelve cada nombre de usuario a "id-" seguido del nombre.
+type usernameIDs struct{}
+
+func (usernameIDs) GetIDsByUsernames(ctx context.Context, usernames []string) (map[string]string, error) {
+	ids := make(map[string]string, len(usernames))
+	for _, name := range usernames {
+		ids[name] = "id-" + name
+	}
+	return ids, nil
+}
+
+// blocksOf oculta a cada usuario los indicados para él.
+type blocksOf map[string][]string
+
+func (b blocksOf) Hidden(ctx context.Context, userID string) ([]string, error) { return b[userID], nil }
+
+func TestResolveMentionsSkipsBlockedUsers(t *testing.T) {
+	// "id-ana" bloqueó al autor y el autor bloqueó a "id-beto"; Hidden cubre ambos sentidos
+	blocks := blocksOf{"autor": {"id-ana", "id-beto"}}
+	u := NewPostUsecase(nil, features.New(defaultFlags{}), PostUsecaseOptions{Users: usernameIDs{}, AuthorBlocks: blocks})
+
+	got, err := u.resolveMentions(context.Background(), "autor", "hola @ana, @beto y @carla")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"id-carla"}; !slices.Equal(got, want) {
+		t.Fatalf("resolveMentions = %q, se esperaba %q", got, want)
+	}
+}
@@ -0,0 +1,245 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// ModerationUsecase agrupa las operaciones administrativas sobre publicaciones.
+type ModerationUsecase struct {
+	postRepo *repositories.PostRepository
+	userRepo *repositories.UserRepository
+	uploader service.ImageUploader
+	audit    *AuditUsecase
+}
+
+func NewModerationUsecase(postRepo *repositories.PostRepository, userRepo *repositories.UserRepository, uploader service.ImageUploader, audit *AuditUsecase) *ModerationUsecase {
+	return &ModerationUsecase{postRepo: postRepo, userRepo: userRepo, uploader: uploader, audit: audit}
+}
+
+// ForceDeletePost elimina definitivamente la publicación y su imagen en Cloudinary,
+// sin importar su estado, incluso si es un duplicado fusionado en otra. Retorna
+// ErrPostNotFound si ya fue eliminada.
+func (u *ModerationUsecase) ForceDeletePost(ctx context.Context, actorID, postID, reason string) error {
+	post, err := u.postRepo.GetByIDIncludingMerged(ctx, postID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrPostNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := u.postRepo.Delete(ctx, postID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrPostNotFound
+		}
+		return err
+	}
+
+	if post.ImagePublicID != "" {
+		if err := u.uploader.Destroy(ctx, post.ImagePublicID); err != nil {
+			log.Printf("Error eliminando imagen %s de Cloudinary: %v", post.ImagePublicID, err)
+		}
+	}
+
+	u.audit.Record(ctx, actorID, models.AuditActionDelete, postID, reason)
+	return nil
+}
+
+// TransferPost reasigna la publicación al usuario toUserID, que debe existir.
+// Retorna ErrPostNotFound o ErrUserNotFound si alguno no existe.
+func (u *ModerationUsecase) TransferPost(ctx context.Context, actorID, postID, toUserID string) error {
+	if err := u.ensureUserExists(ctx, toUserID); err != nil {
+		return err
+	}
+
+	if err := u.postRepo.UpdateAuthor(ctx, postID, toUserID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrPostNotFound
+		}
+		return err
+	}
+
+	u.audit.Record(ctx, actorID, models.AuditActionTransfer, postID, fmt.Sprintf("transferida a %s", toUserID))
+	return nil
+}
+
+// TransferAllPosts reasigna todas las publicaciones de fromUserID a toUserID, por ejemplo al
+// unificar cuentas duplicadas. Retorna cuántas publicaciones se transfirieron.
+func (u *ModerationUsecase) TransferAllPosts(ctx context.Context, actorID, fromUserID, toUserID string) (int, error) {
+	if fromUserID == toUserID {
+		return 0, ErrSameUser
+	}
+	if err := u.ensureUserExists(ctx, toUserID); err != nil {
+		return 0, err
+	}
+
+	transferred, err := u.postRepo.ReassignAuthor(ctx, fromUserID, toUserID)
+	if transferred > 0 {
+		// se registra aunque la operación haya fallado a medias, para no perder las que sí se movieron
+		u.audit.Record(ctx, actorID, models.AuditActionTransfer, fromUserID,
+			fmt.Sprintf("%d publicaciones transferidas a %s", transferred, toUserID))
+	}
+	return transferred, err
+}
+
+// MergePosts fusiona las publicaciones duplicateIDs en primaryID: la principal recibe sus
+// reacciones, reposts y contadores, y los duplicados quedan marcados como fusionados, sin borrar
+// su contenido ni sus imágenes, y dejan de aparecer en las lecturas. Cada duplicado queda
+// registrado en el log de auditoría. Los IDs repetidos se procesan una sola vez. Retorna
+// ErrMergeIntoSelf, ErrPostNotFound si alguna publicación no existe o ErrMergeTooLarge.
+func (u *ModerationUsecase) MergePosts(ctx context.Context, actorID, primaryID string, duplicateIDs []string, reason string) (*models.PostMerge, error) {
+	ids := make([]string, 0, len(duplicateIDs))
+	seen := make(map[string]bool, len(duplicateIDs))
+	for _, id := range duplicateIDs {
+		if id == primaryID {
+			return nil, ErrMergeIntoSelf
+		}
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	merge, merged, err := u.postRepo.Merge(ctx, primaryID, ids)
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return nil, ErrPostNotFound
+	case errors.Is(err, repositories.ErrTooManyWrites):
+		return nil, ErrMergeTooLarge
+	case err != nil:
+		return nil, err
+	}
+
+	note := fmt.Sprintf("fusionada en %s", primaryID)
+	if reason != "" {
+		note += ": " + reason
+	}
+	for _, p := range merged {
+		u.audit.Record(ctx, actorID, models.AuditActionMerge, p.ID, note)
+	}
+	return merge, nil
+}
+
+// Resultados de la moderación de imágenes que notifica Cloudinary.
+const (
+	ImageModerationApproved = "approved"
+	ImageModerationRejected = "rejected"
+)
+
+// cloudinaryActor es el actor con el que se registran en la auditoría las acciones que
+// resultan de las notificaciones de Cloudinary.
+const cloudinaryActor = "cloudinary"
+
+// ApplyImageModeration guarda en la publicación que usa la imagen publicID el resultado de su
+// moderación en Cloudinary. Una imagen rechazada marca la publicación como reportada, lo que se
+// registra en el log de auditoría. Retorna ErrPostNotFound si ninguna publicación usa la imagen.
+func (u *ModerationUsecase) ApplyImageModeration(ctx context.Context, publicID, result string) error {
+	post, err := u.postRepo.GetByImagePublicID(ctx, publicID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrPostNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	rejected := result == ImageModerationRejected
+	if err := u.postRepo.UpdateImageModeration(ctx, post.ID, result, rejected); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrPostNotFound
+		}
+		return err
+	}
+	if rejected {
+		u.audit.Record(ctx, cloudinaryActor, models.AuditActionFlag, post.ID, "imagen rechazada por la moderación de Cloudinary")
+	}
+	return nil
+}
+
+// ensureUserExists retorna ErrUserNotFound si el usuario no existe.
+func (u *ModerationUsecase) ensureUserExists(ctx context.Context, userID string) error {
+	_, err := u.userRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Estados posibles de cada publicación en ClearFlags.
+const (
+	FlagClearCleared  = "cleared"
+	FlagClearNotFound = "not_found"
+	FlagClearFailed   = "error"
+)
+
+// FlagClearResult es el resultado de quitar la marca de una publicación.
+type FlagClearResult struct {
+	PostID string `json:"postId"`
+	Status string `json:"status"`
+}
+
+// ClearFlags quita la marca de reportada de varias publicaciones a la vez y registra cada una
+// en el log de auditoría. Los IDs repetidos se procesan una sola vez y el resultado conserva
+// el orden recibido.
+func (u *ModerationUsecase) ClearFlags(ctx context.Context, actorID string, postIDs []string, reason string) ([]FlagClearResult, error) {
+	ids := make([]string, 0, len(postIDs))
+	seen := make(map[string]bool, len(postIDs))
+	for _, id := range postIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	outcomes, err := u.postRepo.ClearFlags(ctx, ids, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]FlagClearResult, 0, len(ids))
+	for _, id := range ids {
+		res := FlagClearResult{PostID: id, Status: FlagClearCleared}
+		switch err := outcomes[id]; {
+		case errors.Is(err, repositories.ErrNotFound):
+			res.Status = FlagClearNotFound
+		case err != nil:
+			log.Printf("Error quitando la marca de %s: %v", id, err)
+			res.Status = FlagClearFailed
+		default:
+			u.audit.Record(ctx, actorID, models.AuditActionUnflag, id, reason)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// ReviewQueue retorna la página indicada de publicaciones reportadas que ningún moderador ha
+// revisado, de la más antigua a la más reciente, junto con el total pendiente. Al no guardarse
+// la fecha del reporte, el orden usa la fecha de creación de la publicación.
+func (u *ModerationUsecase) ReviewQueue(ctx context.Context, limit, offset int) ([]*models.Post, int64, error) {
+	flagged, err := u.postRepo.GetFlagged(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pending := make([]*models.Post, 0, len(flagged))
+	for _, p := range flagged {
+		if p.ReviewedAt == nil {
+			pending = append(pending, p)
+		}
+	}
+
+	total := int64(len(pending))
+	start := min(offset, len(pending))
+	end := min(start+limit, len(pending))
+	return pending[start:end], total, nil
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// newEmulatorClient conecta con el emulador de Firestore de FIRESTORE_EMULATOR_HOST, o salta el
+// test si no está configurado.
+func newEmulatorClient(t *testing.T) *firestore.Client {
+	t.Helper()
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST no está definido; se necesita el emulador de Firestore")
+	}
+	client, err := firestore.NewClient(context.Background(), "talkus-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// destroyRecorder registra las imágenes que se piden eliminar en Cloudinary.
+type destroyRecorder struct {
+	destroyed []string
+}
+
+func (u *destroyRecorder) Upload(ctx context.Context, file io.Reader, params service.ImageUploadParams) (*service.ImageUploadResult, error) {
+	return nil, errors.New("no se esperaban subidas")
+}
+
+func (u *destroyRecorder) Destroy(ctx context.Context, publicID string) error {
+	u.destroyed = append(u.destroyed, publicID)
+	return nil
+}
+
+func TestForceDeletePostPurgesMergedDuplicate(t *testing.T) {
+	ctx := context.Background()
+	db := newEmulatorClient(t)
+	posts := repositories.NewPostRepository(db)
+	uploader := &destroyRecorder{}
+	moderation := NewModerationUsecase(posts, repositories.NewUserRepository(db), uploader,
+		NewAuditUsecase(repositories.NewAuditLogRepository(db)))
+
+	primary := &models.Post{Title: "principal"}
+	duplicate := &models.Post{Title: "duplicada", ImagePublicID: "posts_images/duplicada"}
+	for _, p := range []*models.Post{primary, duplicate} {
+		if err := posts.Create(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { posts.Delete(ctx, p.ID) })
+	}
+	if _, err := moderation.MergePosts(ctx, "admin", primary.ID, []string{duplicate.ID}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moderation.ForceDeletePost(ctx, "admin", duplicate.ID, "contenido ilegal"); err != nil {
+		t.Fatalf("ForceDeletePost del duplicado fusionado: %v", err)
+	}
+	if len(uploader.destroyed) != 1 || uploader.destroyed[0] != duplicate.ImagePublicID {
+		t.Fatalf("imágenes eliminadas = %v, se esperaba %s", uploader.destroyed, duplicate.ImagePublicID)
+	}
+	if _, err := posts.GetByIDIncludingMerged(ctx, duplicate.ID); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("el duplicado sigue guardado: err = %v", err)
+	}
+	if err := moderation.ForceDeletePost(ctx, "admin", duplicate.ID, ""); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("segunda eliminación: err = %v, se esperaba ErrPostNotFound", err)
+	}
+}
@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+// ErrNotificationNotFound indica que la notificación no existe o no pertenece al usuario.
+var ErrNotificationNotFound = errors.New("notificación no encontrada")
+
+type NotificationUsecase struct {
+	repo    *repositories.NotificationRepository
+	webhook MentionNotifier
+}
+
+// NewNotificationUsecase crea el caso de uso de notificaciones. Además de guardar cada
+// notificación, las menciones se reenvían al webhook configurado.
+func NewNotificationUsecase(repo *repositories.NotificationRepository, webhook MentionNotifier) *NotificationUsecase {
+	return &NotificationUsecase{repo: repo, webhook: webhook}
+}
+
+// NotifyMention registra una notificación de mención para el usuario.
+func (u *NotificationUsecase) NotifyMention(ctx context.Context, userID, postID string) error {
+	err := u.repo.Create(ctx, &models.Notification{
+		UserID:     userID,
+		Type:       models.NotificationMention,
+		PayloadRef: postID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return u.webhook.NotifyMention(ctx, userID, postID)
+}
+
+// GetUserNotifications retorna las notificaciones del usuario junto con su número de no leídas.
+func (u *NotificationUsecase) GetUserNotifications(ctx context.Context, userID string) (*models.NotificationList, error) {
+	notifications, err := u.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := u.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationList{Notifications: notifications, UnreadCount: unread}, nil
+}
+
+// CountUnread retorna cuántas notificaciones no leídas tiene el usuario, sin leerlas.
+func (u *NotificationUsecase) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return u.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marca como leída una notificación del usuario.
+func (u *NotificationUsecase) MarkRead(ctx context.Context, userID, notificationID string) error {
+	err := u.repo.MarkRead(ctx, userID, notificationID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrNotificationNotFound
+	}
+	return err
+}
+
+// MarkAllRead marca como leídas todas las notificaciones del usuario y retorna cuántas cambiaron.
+func (u *NotificationUsecase) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	return u.repo.MarkAllRead(ctx, userID)
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// archiveCacheTTL define cuánto tiempo se reutiliza el conteo de publicaciones por mes. Leerlo
+// cuesta una consulta a los contadores del archivo, así que basta con una caché corta para la
+// barra lateral, que se pide en cada página.
+const archiveCacheTTL = time.Minute
+
+// GetArchive retorna cuántas publicaciones públicas hay en cada mes, del más reciente al más
+// antiguo. Los meses sin publicaciones se omiten.
+func (u *PostUsecase) GetArchive(ctx context.Context) ([]models.ArchiveMonth, error) {
+	if months, ok := u.archive.Get("months"); ok {
+		return months, nil
+	}
+	months, err := u.repo.CountPublicByMonth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u.archive.Set("months", months)
+	return months, nil
+}
+
+// GetArchiveMonth retorna una página de las publicaciones públicas creadas en el mes indicado
+// (UTC), de la más antigua a la más reciente, junto con el total del mes. Se omiten las de
+// autores con los que viewerID tiene un bloqueo.
+func (u *PostUsecase) GetArchiveMonth(ctx context.Context, year int, month time.Month, limit, offset int, viewerID string) ([]*models.Post, int64, error) {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	posts, err := u.repo.GetCreatedBetween(ctx, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err = u.withoutHiddenAuthors(ctx, visiblePosts(posts, ""), viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(posts))
+	posts = posts[min(offset, len(posts)):]
+	posts = posts[:min(limit, len(posts))]
+	presentPosts(u.readingWPM, posts...)
+	for _, p := range posts {
+		p.Views += int(u.views.Pending(p.ID))
+	}
+	return posts, total, nil
+}
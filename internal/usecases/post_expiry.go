@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+	"github.com/JuanPidarraga/talkus-backend/internal/service"
+)
+
+// expiredBatchSize es el número máximo de publicaciones vencidas eliminadas en cada barrido.
+const expiredBatchSize = 100
+
+// PostExpirer elimina las publicaciones cuyo ExpiresAt ya pasó, junto con su imagen.
+//
+// Puede ejecutarse en varias instancias a la vez: la eliminación exige que el documento
+// exista, así que si dos instancias toman la misma publicación solo una la elimina y solo
+// esa borra la imagen en Cloudinary.
+type PostExpirer struct {
+	postRepo *repositories.PostRepository
+	uploader service.ImageUploader
+}
+
+func NewPostExpirer(postRepo *repositories.PostRepository, uploader service.ImageUploader) *PostExpirer {
+	return &PostExpirer{postRepo: postRepo, uploader: uploader}
+}
+
+// DeleteExpired elimina un lote de publicaciones vencidas y retorna cuántas eliminó.
+func (e *PostExpirer) DeleteExpired(ctx context.Context) (int, error) {
+	posts, err := e.postRepo.GetExpired(ctx, time.Now(), expiredBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, p := range posts {
+		err := e.postRepo.Delete(ctx, p.ID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			// otra instancia ya la eliminó
+			continue
+		}
+		if err != nil {
+			log.Printf("Error eliminando publicación vencida %s: %v", p.ID, err)
+			continue
+		}
+		deleted++
+
+		if p.ImagePublicID != "" {
+			if err := e.uploader.Destroy(ctx, p.ImagePublicID); err != nil {
+				log.Printf("Error eliminando imagen %s de Cloudinary: %v", p.ImagePublicID, err)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// Run ejecuta DeleteExpired cada interval hasta que ctx se cancela.
+func (e *PostExpirer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := e.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("Error buscando publicaciones vencidas: %v", err)
+			} else if deleted > 0 {
+				log.Printf("%d publicaciones vencidas eliminadas", deleted)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// ImportPosts guarda publicaciones migradas desde otra plataforma, conservando su autor y
+// fecha de creación. El título y el contenido se limpian como en CreatePost, las etiquetas se
+// normalizan como en UpdateTags y las menciones no se resuelven ni se notifican. Retorna un
+// error por publicación, en el mismo orden, nil para las que se guardaron.
+func (u *PostUsecase) ImportPosts(ctx context.Context, posts []*models.Post) []error {
+	errs := make([]error, len(posts))
+	valid := make([]*models.Post, 0, len(posts))
+	validIdx := make([]int, 0, len(posts))
+
+	for i, p := range posts {
+		if err := sanitizePost(p); err != nil {
+			errs[i] = err
+			continue
+		}
+		tags, err := NormalizeTags(p.Tags)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		p.Tags = tags
+		p.Mentions = []string{}
+		p.Visibility = models.VisibilityPublic
+		valid = append(valid, p)
+		validIdx = append(validIdx, i)
+	}
+
+	if len(valid) > 0 {
+		for j, err := range u.repo.CreateMany(ctx, valid) {
+			errs[validIdx[j]] = err
+		}
+		u.postsChanged()
+	}
+	return errs
+}
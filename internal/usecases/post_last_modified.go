@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+	"time"
+)
+
+// lastModifiedCacheTTL define cuánto tiempo se reutiliza la fecha del último cambio. Los cambios
+// hechos por este usecase la invalidan en el acto; los demás (reacciones, moderación,
+// expiración u otras instancias) se notan en a lo sumo este tiempo.
+const lastModifiedCacheTTL = 10 * time.Second
+
+// LastModified retorna la fecha del último cambio en las publicaciones (creación, edición,
+// reacción o eliminación), o el valor cero si no hay publicaciones. Los cambios de vistas no
+// cuentan.
+func (u *PostUsecase) LastModified(ctx context.Context) (time.Time, error) {
+	if t, ok := u.lastModified.Get("posts"); ok {
+		return t, nil
+	}
+	t, err := u.repo.LastModified(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	u.lastModified.Set("posts", t)
+	return t, nil
+}
+
+// postsChanged invalida la fecha del último cambio tras escribir una publicación.
+func (u *PostUsecase) postsChanged() {
+	u.lastModified.Delete("posts")
+}
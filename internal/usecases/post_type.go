@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// postTypeRules indica, por tipo de publicación, qué campos no pueden quedar vacíos.
+var postTypeRules = map[string][]string{
+	models.PostTypeStandard:     {"title", "content"},
+	models.PostTypeQuestion:     {"content"},
+	models.PostTypeAnnouncement: {"title"},
+}
+
+// PostValidationError indica que la publicación no cumple las reglas de su tipo. Fields asocia
+// cada campo inválido con el motivo.
+type PostValidationError struct {
+	Type   string
+	Fields map[string]string
+}
+
+func (e *PostValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf("publicación de tipo %q inválida: %s", e.Type, strings.Join(fields, ", "))
+}
+
+// ValidatePostType valida la publicación según su tipo, asignando PostTypeStandard si no
+// tiene uno. Retorna un *PostValidationError con los campos que faltan o si el tipo no existe.
+func ValidatePostType(p *models.Post) error {
+	if p.Type == "" {
+		p.Type = models.PostTypeStandard
+	}
+	required, ok := postTypeRules[p.Type]
+	if !ok {
+		return &PostValidationError{
+			Type:   p.Type,
+			Fields: map[string]string{"type": "debe ser standard, question o announcement"},
+		}
+	}
+
+	values := map[string]string{"title": p.Title, "content": p.Content}
+	fields := make(map[string]string)
+	for _, field := range required {
+		if strings.TrimSpace(values[field]) == "" {
+			fields[field] = fmt.Sprintf("es obligatorio en publicaciones de tipo %s", p.Type)
+		}
+	}
+	if len(fields) > 0 {
+		return &PostValidationError{Type: p.Type, Fields: fields}
+	}
+	return nil
+}
@@ -1,26 +1,594 @@
 package usecases
 
-import(
+import (
 	"context"
+	"errors"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/cache"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
 	"github.com/JuanPidarraga/talkus-backend/internal/models"
 	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+	"github.com/JuanPidarraga/talkus-backend/internal/tracing"
+)
+
+// PostRepository define las operaciones de persistencia que necesita PostUsecase.
+// La implementación en Firestore es repositories.PostRepository.
+type PostRepository interface {
+	GetAll(ctx context.Context, f models.PostFilter) ([]*models.Post, error)
+	Each(ctx context.Context, byViews, oldestFirst bool, fn func(*models.Post) error) error
+	GetByID(ctx context.Context, id string) (*models.Post, error)
+	GetByIDs(ctx context.Context, ids []string) (map[string]*models.Post, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Post, error)
+	SlugInUse(ctx context.Context, slug string) (bool, error)
+	GetRandom(ctx context.Context) (*models.Post, error)
+	GetByAnyTag(ctx context.Context, tags []string, limit int) ([]*models.Post, error)
+	GetByTags(ctx context.Context, tags []string, matchAll bool) ([]*models.Post, error)
+	GetCreatedBetween(ctx context.Context, from, to time.Time) ([]*models.Post, error)
+	GetTagsSince(ctx context.Context, since time.Time) ([]*models.Post, error)
+	CountPublicByMonth(ctx context.Context) ([]models.ArchiveMonth, error)
+	Create(ctx context.Context, p *models.Post) error
+	CreateMany(ctx context.Context, posts []*models.Post) []error
+	SuggestByTitlePrefix(ctx context.Context, prefix string, limit int, keep func(*models.Post) bool) ([]*models.Post, error)
+	UpdateTags(ctx context.Context, id string, tags []string) error
+	UpdateLinkPreview(ctx context.Context, id string, preview *models.LinkPreview) error
+	CountByAuthorSince(ctx context.Context, authorID string, since time.Time) (int64, error)
+	LastModified(ctx context.Context) (time.Time, error)
+}
+
+var _ PostRepository = (*repositories.PostRepository)(nil)
+
+// relatedCandidates es el número de publicaciones recientes evaluadas al buscar relacionadas.
+const relatedCandidates = 100
+
+// MentionResolver resuelve nombres de usuario mencionados a sus IDs.
+type MentionResolver interface {
+	GetIDsByUsernames(ctx context.Context, usernames []string) (map[string]string, error)
+}
+
+// LikeCounter cuenta los likes dados a cada publicación a partir de una fecha.
+type LikeCounter interface {
+	CountLikesSince(ctx context.Context, since time.Time) (map[string]int, error)
+}
+
+var _ LikeCounter = (*repositories.ReactionRepository)(nil)
+
+// AuthorHider retorna los autores cuyas publicaciones no debe ver un usuario por un bloqueo.
+type AuthorHider interface {
+	Hidden(ctx context.Context, userID string) ([]string, error)
+}
+
+var _ AuthorHider = (*BlockUsecase)(nil)
+
+// MentionNotifier recibe un aviso por cada usuario mencionado en una publicación.
+type MentionNotifier interface {
+	NotifyMention(ctx context.Context, userID, postID string) error
+}
+
+// PostSort indica el orden en que se listan las publicaciones.
+type PostSort string
+
+const (
+	// PostSortRecent ordena de la publicación más reciente a la más antigua.
+	PostSortRecent PostSort = "recent"
+	// PostSortViews ordena de la publicación más vista a la menos vista.
+	PostSortViews PostSort = "views"
+	// PostSortOldest ordena de la publicación más antigua a la más reciente.
+	PostSortOldest PostSort = "oldest"
 )
 
 type PostUsecase struct {
-    repo *repositories.PostRepository
+	repo     PostRepository
+	users    MentionResolver
+	notifier MentionNotifier
+	previews LinkPreviewer
+	likes    LikeCounter
+	views    *ViewCounter
+	flags    *features.Flags
+	// readingWPM son las palabras por minuto usadas para estimar el tiempo de lectura.
+	readingWPM int
+	// rejectSlugConflicts rechaza con ErrSlugTaken los slugs elegidos por el autor que ya están
+	// en uso, en lugar de generar otro.
+	rejectSlugConflicts bool
+	// dailyPostLimit es el máximo de publicaciones por usuario en 24 horas; 0 no limita.
+	dailyPostLimit int
+	// blocked son los dominios a los que no se permite enlazar.
+	blocked *DomainBlocklist
+	// authorBlocks indica qué autores oculta a cada usuario un bloqueo.
+	authorBlocks AuthorHider
+	// archive guarda por un rato el conteo de publicaciones por mes.
+	archive *cache.TTLCache[[]models.ArchiveMonth]
+	// trendingTags guarda por un rato el ranking de etiquetas de cada ventana.
+	trendingTags *cache.TTLCache[[]models.TagCount]
+	// lastModified guarda por un rato la fecha del último cambio en las publicaciones.
+	lastModified *cache.TTLCache[time.Time]
+	// topPosts guarda por un rato las publicaciones con más likes de cada periodo.
+	topPosts *cache.TTLCache[[]*models.Post]
+	// mentionQueue son los avisos de mención que esperan a los mentionWorkers. Es nil si no
+	// hay notifier.
+	mentionQueue chan mentionNotification
+}
+
+// mentionWorkers es cuántos avisos de mención se envían a la vez, y mentionQueueSize cuántos
+// pueden esperar; los que no caben se descartan.
+const (
+	mentionWorkers   = 4
+	mentionQueueSize = 1000
+)
+
+// mentionNotification es el aviso al usuario userID de que lo mencionaron en postID.
+type mentionNotification struct {
+	userID string
+	postID string
+}
+
+// PostUsecaseOptions agrupa las dependencias y los ajustes opcionales de PostUsecase. Las
+// dependencias nil desactivan lo que proveen: sin Notifier no se avisa de las menciones y sin
+// AuthorBlocks no se aplican bloqueos.
+type PostUsecaseOptions struct {
+	Users    MentionResolver
+	Notifier MentionNotifier
+	Previews LinkPreviewer
+	Likes    LikeCounter
+	Views    *ViewCounter
+	// ReadingWPM son las palabras por minuto usadas para estimar el tiempo de lectura.
+	ReadingWPM int
+	// RejectSlugConflicts rechaza con ErrSlugTaken los slugs elegidos por el autor que ya están
+	// en uso, en lugar de generar otro.
+	RejectSlugConflicts bool
+	// DailyPostLimit es el máximo de publicaciones por usuario en 24 horas; 0 no limita.
+	DailyPostLimit int
+	// Blocked son los dominios a los que no se permite enlazar.
+	Blocked *DomainBlocklist
+	// AuthorBlocks indica qué autores oculta a cada usuario un bloqueo.
+	AuthorBlocks AuthorHider
+}
+
+func NewPostUsecase(repo PostRepository, flags *features.Flags, opts PostUsecaseOptions) *PostUsecase {
+	u := &PostUsecase{repo: repo, users: opts.Users, notifier: opts.Notifier, previews: opts.Previews, likes: opts.Likes,
+		views: opts.Views, flags: flags, readingWPM: opts.ReadingWPM, rejectSlugConflicts: opts.RejectSlugConflicts,
+		dailyPostLimit: opts.DailyPostLimit, blocked: opts.Blocked, authorBlocks: opts.AuthorBlocks,
+		archive:      cache.NewTTLCache[[]models.ArchiveMonth](archiveCacheTTL),
+		trendingTags: cache.NewTTLCache[[]models.TagCount](trendingTagsCacheTTL),
+		lastModified: cache.NewTTLCache[time.Time](lastModifiedCacheTTL),
+		topPosts:     cache.NewTTLCache[[]*models.Post](topPostsCacheTTL)}
+	if opts.Notifier != nil {
+		u.mentionQueue = make(chan mentionNotification, mentionQueueSize)
+		for range mentionWorkers {
+			go u.sendMentions()
+		}
+	}
+	return u
+}
+
+// GetAllPosts retorna las publicaciones que cumplen filter y que viewerID puede ver, sin las de
+// autores con los que tiene un bloqueo, en el orden indicado. Las vistas incluyen las que aún no
+// se han escrito en Firestore.
+func (u *PostUsecase) GetAllPosts(ctx context.Context, filter models.PostFilter, order PostSort, viewerID string) ([]*models.Post, error) {
+	posts, err := u.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	posts, err = u.withoutHiddenAuthors(ctx, visiblePosts(posts, viewerID), viewerID)
+	if err != nil {
+		return nil, err
+	}
+	presentPosts(u.readingWPM, posts...)
+	for _, p := range posts {
+		p.Views += int(u.views.Pending(p.ID))
+	}
+
+	switch order {
+	case PostSortOldest:
+		slices.Reverse(posts)
+	case PostSortViews:
+		// el orden estable conserva la fecha como desempate entre publicaciones con las mismas vistas
+		sort.SliceStable(posts, func(i, j int) bool {
+			return posts[i].Views > posts[j].Views
+		})
+	}
+	return posts, nil
+}
+
+// StreamPosts llama a fn con cada publicación, sin filtrar por visibilidad, en el orden
+// indicado. Pensado para exportaciones administrativas: a diferencia de GetAllPosts no reúne
+// todas las publicaciones en memoria, y con PostSortViews el orden usa las vistas ya guardadas,
+// sin las pendientes de ViewCounter. Tampoco se aplican los bloqueos de quien exporta, para que
+// la exportación incluya todas las publicaciones. Si hasImage no es nil, solo se entregan las
+// publicaciones con imagen (true) o sin ella (false).
+func (u *PostUsecase) StreamPosts(ctx context.Context, order PostSort, hasImage *bool, fn func(*models.Post) error) error {
+	return u.repo.Each(ctx, order == PostSortViews, order == PostSortOldest, func(p *models.Post) error {
+		if hasImage != nil && (p.ImageURL != "") != *hasImage {
+			return nil
+		}
+		presentPosts(u.readingWPM, p)
+		return fn(p)
+	})
+}
+
+// GetPost retorna la publicación y registra una vista de viewerKey, que identifica al
+// visitante para no contar varias veces sus lecturas repetidas. Si viewerID no puede verla, o
+// tiene un bloqueo con el autor, retorna ErrPostNotFound, para no revelar que existe.
+func (u *PostUsecase) GetPost(ctx context.Context, id, viewerKey, viewerID string) (_ *models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "PostUsecase.GetPost", tracing.PostIDKey.String(id))
+	defer func() { tracing.End(span, err) }()
+
+	p, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	if !canView(p, viewerID) {
+		return nil, ErrPostNotFound
+	}
+	if hidden, err := u.hiddenFrom(ctx, p, viewerID); err != nil || hidden {
+		if err == nil {
+			err = ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	presentPosts(u.readingWPM, p)
+	u.views.Record(p.ID, viewerKey)
+	p.Views += int(u.views.Pending(p.ID))
+	return p, nil
+}
+
+// randomAttempts es cuántas publicaciones al azar se prueban antes de rendirse cuando las que
+// salen son de autores ocultos por un bloqueo.
+const randomAttempts = 3
+
+// GetRandomPost retorna una publicación pública y no reportada al azar, de un autor sin bloqueos
+// con viewerID. Retorna ErrPostNotFound si no hay ninguna.
+func (u *PostUsecase) GetRandomPost(ctx context.Context, viewerID string) (*models.Post, error) {
+	for range randomAttempts {
+		p, err := u.repo.GetRandom(ctx)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		hidden, err := u.hiddenFrom(ctx, p, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if hidden {
+			continue
+		}
+
+		presentPosts(u.readingWPM, p)
+		p.Views += int(u.views.Pending(p.ID))
+		return p, nil
+	}
+	return nil, ErrPostNotFound
+}
+
+// ResolvePost retorna la publicación a la que apunta ref, que puede ser su ID o su slug.
+func (u *PostUsecase) ResolvePost(ctx context.Context, ref, viewerKey, viewerID string) (*models.Post, error) {
+	p, err := u.GetPost(ctx, ref, viewerKey, viewerID)
+	if !errors.Is(err, ErrPostNotFound) {
+		return p, err
+	}
+
+	bySlug, err := u.repo.GetBySlug(ctx, NormalizeSlug(ref))
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.GetPost(ctx, bySlug.ID, viewerKey, viewerID)
 }
 
-func NewPostUsecase(repo *repositories.PostRepository) *PostUsecase {
-    return &PostUsecase{repo: repo}
+// GetPublicPost retorna la publicación a la que apunta ref (su ID o su slug) si un visitante
+// anónimo puede verla, sin registrar una vista. Pensado para servicios que leen la publicación
+// en nombre de otros, como los que generan vistas previas de enlaces compartidos. Si quien la
+// pide (viewerID, vacío si es anónimo) tiene un bloqueo con el autor retorna ErrPostNotFound.
+func (u *PostUsecase) GetPublicPost(ctx context.Context, ref, viewerID string) (*models.Post, error) {
+	p, err := u.repo.GetByID(ctx, ref)
+	if errors.Is(err, repositories.ErrNotFound) {
+		p, err = u.repo.GetBySlug(ctx, NormalizeSlug(ref))
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !canView(p, "") {
+		return nil, ErrPostNotFound
+	}
+	if hidden, err := u.hiddenFrom(ctx, p, viewerID); err != nil || hidden {
+		if err == nil {
+			err = ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	presentPosts(u.readingWPM, p)
+	return p, nil
 }
 
-func (u *PostUsecase) GetAllPosts(ctx context.Context) ([]*models.Post, error) {
-    return u.repo.GetAll(ctx)
+// PostSuggestion es una publicación reducida a lo necesario para autocompletar búsquedas.
+type PostSuggestion struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug,omitempty"`
+	Title string `json:"title"`
 }
 
-func (u *PostUsecase) CreatePost(ctx context.Context, p *models.Post) (*models.Post, error) {
-    if err := u.repo.Create(ctx, p); err != nil {
-        return nil, err
-    }
-    return p, nil
-}
\ No newline at end of file
+// SuggestPosts retorna hasta limit publicaciones públicas cuyo título empieza con prefix, sin
+// las de autores con los que viewerID tiene un bloqueo. Ambos filtros se aplican en la consulta,
+// antes del límite, para que las publicaciones ocultas no dejen la respuesta corta.
+func (u *PostUsecase) SuggestPosts(ctx context.Context, prefix string, limit int, viewerID string) ([]PostSuggestion, error) {
+	var hidden []string
+	if u.authorBlocks != nil && viewerID != "" {
+		var err error
+		if hidden, err = u.authorBlocks.Hidden(ctx, viewerID); err != nil {
+			return nil, err
+		}
+	}
+	posts, err := u.repo.SuggestByTitlePrefix(ctx, prefix, limit, func(p *models.Post) bool {
+		return canView(p, "") && !slices.Contains(hidden, p.AuthorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]PostSuggestion, 0, len(posts))
+	for _, p := range posts {
+		suggestions = append(suggestions, PostSuggestion{ID: p.ID, Slug: p.Slug, Title: p.Title})
+	}
+	return suggestions, nil
+}
+
+// checkDailyLimit retorna ErrDailyPostLimit si el autor ya creó dailyPostLimit publicaciones
+// en las últimas 24 horas. Las publicaciones anónimas no se limitan por cuenta.
+func (u *PostUsecase) checkDailyLimit(ctx context.Context, authorID string) error {
+	if u.dailyPostLimit <= 0 || authorID == "" {
+		return nil
+	}
+	count, err := u.repo.CountByAuthorSince(ctx, authorID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if count >= int64(u.dailyPostLimit) {
+		return ErrDailyPostLimit
+	}
+	return nil
+}
+
+// GetRecentPosts retorna como máximo las limit publicaciones públicas más recientes, sin las de
+// autores con los que viewerID tiene un bloqueo.
+func (u *PostUsecase) GetRecentPosts(ctx context.Context, limit int, viewerID string) ([]*models.Post, error) {
+	posts, err := u.repo.GetAll(ctx, models.PostFilter{})
+	if err != nil {
+		return nil, err
+	}
+	posts, err = u.withoutHiddenAuthors(ctx, visiblePosts(posts, ""), viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	presentPosts(u.readingWPM, posts...)
+	return posts, nil
+}
+
+// CreatePost guarda la publicación tras quitar los caracteres de control del título y el
+// contenido. Retorna un *PostValidationError si no cumple las reglas de su tipo. Si el autor
+// eligió un slug se normaliza y se valida (ErrInvalidSlug, ErrSlugReserved); si no, se genera
+// uno desde el título.
+// Retorna ErrDailyPostLimit si el autor ya publicó el máximo diario, salvo que staff indique
+// que es moderador o administrador. Los enlaces a dominios bloqueados rechazan la publicación
+// con ErrBlockedDomain o la marcan como reportada, según el modo de la lista.
+func (u *PostUsecase) CreatePost(ctx context.Context, p *models.Post, staff bool) (_ *models.Post, err error) {
+	ctx, span := tracing.Start(ctx, "PostUsecase.CreatePost", tracing.UserIDKey.String(p.AuthorID))
+	defer func() { tracing.End(span, err) }()
+
+	if err := sanitizePost(p); err != nil {
+		return nil, err
+	}
+	if !staff {
+		if err := u.checkDailyLimit(ctx, p.AuthorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.checkBlockedDomains(p); err != nil {
+		return nil, err
+	}
+	if p.Slug != "" {
+		p.Slug = NormalizeSlug(p.Slug)
+		if err := ValidateSlug(p.Slug); err != nil {
+			return nil, err
+		}
+	}
+	if p.Visibility == "" {
+		p.Visibility = models.VisibilityPublic
+	}
+	mentions, err := u.resolveMentions(ctx, p.AuthorID, p.Title+"\n"+p.Content)
+	if err != nil {
+		return nil, err
+	}
+	p.Mentions = mentions
+
+	if err := u.createWithSlug(ctx, p); err != nil {
+		return nil, err
+	}
+	u.postsChanged()
+	span.SetAttributes(tracing.PostIDKey.String(p.ID))
+
+	u.notifyMentions(p.ID, p.Mentions)
+	u.attachLinkPreview(p)
+	presentPosts(u.readingWPM, p)
+	return p, nil
+}
+
+// resolveMentions retorna los IDs de los usuarios mencionados en el texto por authorID.
+// Las menciones que no corresponden a un usuario real se ignoran, al igual que las de usuarios
+// con un bloqueo, en cualquier sentido, con el autor.
+func (u *PostUsecase) resolveMentions(ctx context.Context, authorID, text string) ([]string, error) {
+	if !u.flags.IsEnabled(features.Mentions) {
+		return []string{}, nil
+	}
+
+	usernames := ParseMentions(text)
+	if len(usernames) == 0 {
+		return []string{}, nil
+	}
+
+	ids, err := u.users.GetIDsByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	var blocked []string
+	if u.authorBlocks != nil && authorID != "" {
+		if blocked, err = u.authorBlocks.Hidden(ctx, authorID); err != nil {
+			return nil, err
+		}
+	}
+
+	mentions := make([]string, 0, len(ids))
+	for _, username := range usernames {
+		if id, ok := ids[username]; ok && !slices.Contains(blocked, id) {
+			mentions = append(mentions, id)
+		}
+	}
+	return mentions, nil
+}
+
+// notifyMentions encola el aviso a cada usuario mencionado sin bloquear la creación de la
+// publicación. Si la cola está llena, el aviso se descarta.
+func (u *PostUsecase) notifyMentions(postID string, userIDs []string) {
+	if u.mentionQueue == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		select {
+		case u.mentionQueue <- mentionNotification{userID: userID, postID: postID}:
+		default:
+			log.Printf("Cola de menciones llena; no se avisa a %s de %s", userID, postID)
+		}
+	}
+}
+
+// sendMentions envía los avisos de mentionQueue, uno a la vez.
+func (u *PostUsecase) sendMentions() {
+	for n := range u.mentionQueue {
+		if err := u.notifier.NotifyMention(context.Background(), n.userID, n.postID); err != nil {
+			log.Printf("Error notificando mención a %s: %v", n.userID, err)
+		}
+	}
+}
+
+// UpdateTags normaliza y reemplaza las etiquetas de la publicación, retornando la lista guardada.
+// Solo pueden hacerlo su autor (editorID) o un moderador o administrador (staff); a los demás
+// retorna ErrNotPostAuthor, o ErrPostNotFound si ni siquiera pueden verla.
+func (u *PostUsecase) UpdateTags(ctx context.Context, id string, tags []string, editorID string, staff bool) ([]string, error) {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := u.repo.GetByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !staff && !canView(post, editorID) {
+		return nil, ErrPostNotFound
+	}
+	if !staff && (editorID == "" || post.AuthorID != editorID) {
+		return nil, ErrNotPostAuthor
+	}
+
+	if err := u.repo.UpdateTags(ctx, id, normalized); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	u.postsChanged()
+	return normalized, nil
+}
+
+// GetRelatedPosts retorna hasta limit publicaciones que comparten etiquetas con la indicada,
+// ordenadas por número de etiquetas en común y luego por fecha. Excluye la publicación original
+// y las que viewerID no puede ver o son de autores con los que tiene un bloqueo; si el bloqueo es
+// con el autor de la original retorna ErrPostNotFound.
+func (u *PostUsecase) GetRelatedPosts(ctx context.Context, id string, limit int, viewerID string) ([]*models.Post, error) {
+	source, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	if !canView(source, viewerID) {
+		return nil, ErrPostNotFound
+	}
+	if hidden, err := u.hiddenFrom(ctx, source, viewerID); err != nil || hidden {
+		if err == nil {
+			err = ErrPostNotFound
+		}
+		return nil, err
+	}
+	if len(source.Tags) == 0 {
+		return []*models.Post{}, nil
+	}
+
+	candidates, err := u.repo.GetByAnyTag(ctx, source.Tags, relatedCandidates)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err = u.withoutHiddenAuthors(ctx, visiblePosts(candidates, viewerID), viewerID)
+	if err != nil {
+		return nil, err
+	}
+	presentPosts(u.readingWPM, source)
+	presentPosts(u.readingWPM, candidates...)
+
+	sourceTags := make(map[string]bool, len(source.Tags))
+	for _, t := range source.Tags {
+		sourceTags[t] = true
+	}
+
+	type scored struct {
+		post    *models.Post
+		overlap int
+	}
+	ranked := make([]scored, 0, len(candidates))
+	for _, p := range candidates {
+		if p.ID == source.ID {
+			continue
+		}
+		overlap := 0
+		for _, t := range p.Tags {
+			if sourceTags[t] {
+				overlap++
+			}
+		}
+		ranked = append(ranked, scored{post: p, overlap: overlap})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].overlap != ranked[j].overlap {
+			return ranked[i].overlap > ranked[j].overlap
+		}
+		return ranked[i].post.CreatedAt.After(ranked[j].post.CreatedAt)
+	})
+
+	related := make([]*models.Post, 0, limit)
+	for i := 0; i < len(ranked) && i < limit; i++ {
+		related = append(related, ranked[i].post)
+	}
+	return related, nil
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// maxQueryTags es el máximo de etiquetas que se pueden combinar en una búsqueda.
+const maxQueryTags = 10
+
+// GetPostsByTags retorna una página de las publicaciones que viewerID puede ver, de autores sin
+// bloqueos con él, y que tienen alguna de las etiquetas o, si matchAll es true, todas ellas, de
+// la más reciente a la más antigua, junto con el total. Las etiquetas se comparan en su forma
+// canónica; retorna ErrInvalidTags si no hay ninguna, si son demasiadas o si alguna excede el
+// largo máximo.
+func (u *PostUsecase) GetPostsByTags(ctx context.Context, tags []string, matchAll bool, viewerID string, limit, offset int) ([]*models.Post, int64, error) {
+	tags = CanonicalTags(tags)
+	if len(tags) == 0 {
+		return nil, 0, fmt.Errorf("%w: indica al menos una etiqueta", ErrInvalidTags)
+	}
+	if len(tags) > maxQueryTags {
+		return nil, 0, fmt.Errorf("%w: máximo %d etiquetas por búsqueda", ErrInvalidTags, maxQueryTags)
+	}
+	for _, t := range tags {
+		if len([]rune(t)) > maxTagLength {
+			return nil, 0, fmt.Errorf("%w: %q supera los %d caracteres", ErrInvalidTags, t, maxTagLength)
+		}
+	}
+
+	posts, err := u.repo.GetByTags(ctx, tags, matchAll)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts, err = u.withoutHiddenAuthors(ctx, visiblePosts(posts, viewerID), viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(posts))
+	posts = posts[min(offset, len(posts)):]
+	posts = posts[:min(limit, len(posts))]
+	presentPosts(u.readingWPM, posts...)
+	for _, p := range posts {
+		p.Views += int(u.views.Pending(p.ID))
+	}
+	return posts, total, nil
+}
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+var validReactions = map[string]bool{
+	models.ReactionLike:    true,
+	models.ReactionDislike: true,
+	models.ReactionLove:    true,
+	models.ReactionLaugh:   true,
+	models.ReactionAngry:   true,
+	models.ReactionSad:     true,
+}
+
+type ReactionUsecase struct {
+	repo     *repositories.ReactionRepository
+	postRepo *repositories.PostRepository
+	blocks   *BlockUsecase
+}
+
+func NewReactionUsecase(repo *repositories.ReactionRepository, postRepo *repositories.PostRepository, blocks *BlockUsecase) *ReactionUsecase {
+	return &ReactionUsecase{repo: repo, postRepo: postRepo, blocks: blocks}
+}
+
+// React registra la reacción del usuario a la publicación; si ya tenía otra, la reemplaza.
+// Retorna los totales por reacción de la publicación, o ErrBlocked si hay un bloqueo entre el
+// usuario y el autor. Si el usuario no puede ver la publicación retorna ErrPostNotFound, igual
+// que al leerla.
+func (u *ReactionUsecase) React(ctx context.Context, userID, postID, reaction string) (map[string]int, error) {
+	if !validReactions[reaction] {
+		return nil, ErrInvalidReaction
+	}
+	post, err := u.postRepo.GetByID(ctx, postID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !canView(post, userID) {
+		return nil, ErrPostNotFound
+	}
+	if err := u.blocks.CheckInteraction(ctx, userID, post.AuthorID); err != nil {
+		return nil, err
+	}
+
+	tallies, err := u.repo.React(ctx, postID, userID, reaction)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	return tallies, err
+}
@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+func TestReactHidesPostsTheUserCannotView(t *testing.T) {
+	ctx := context.Background()
+	db := newEmulatorClient(t)
+	posts := repositories.NewPostRepository(db)
+	reactions := NewReactionUsecase(repositories.NewReactionRepository(db), posts,
+		NewBlockUsecase(repositories.NewBlockRepository(db), repositories.NewUserRepository(db)))
+
+	private := &models.Post{Title: "privada", AuthorID: "autor", Visibility: models.VisibilityPrivate}
+	if err := posts.Create(ctx, private); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { posts.Delete(ctx, private.ID) })
+
+	if _, err := reactions.React(ctx, "otro", private.ID, models.ReactionLike); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("React de otro usuario: err = %v, se esperaba ErrPostNotFound", err)
+	}
+	tallies, err := reactions.React(ctx, "autor", private.ID, models.ReactionLike)
+	if err != nil {
+		t.Fatalf("React del autor: %v", err)
+	}
+	if tallies[models.ReactionLike] != 1 {
+		t.Fatalf("likes = %d, se esperaba solo el del autor", tallies[models.ReactionLike])
+	}
+}
@@ -0,0 +1,27 @@
+package usecases
+
+import (
+	"strings"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// ReadingTimeSeconds estima cuántos segundos toma leer content a wordsPerMinute palabras por
+// minuto, redondeando hacia arriba. Un texto vacío toma 0 segundos.
+func ReadingTimeSeconds(content string, wordsPerMinute int) int {
+	words := len(strings.Fields(content))
+	if words == 0 || wordsPerMinute <= 0 {
+		return 0
+	}
+	return (words*60 + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// presentPosts completa los campos que se calculan al responder y no se guardan en Firestore:
+// las etiquetas en forma canónica (incluso las guardadas antes de normalizarlas al escribir)
+// y el tiempo estimado de lectura.
+func presentPosts(readingWPM int, posts ...*models.Post) {
+	for _, p := range posts {
+		p.Tags = CanonicalTags(p.Tags)
+		p.ReadingTimeSeconds = ReadingTimeSeconds(p.Content, readingWPM)
+	}
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+type RepostUsecase struct {
+	repo       *repositories.RepostRepository
+	postRepo   *repositories.PostRepository
+	blocks     *BlockUsecase
+	readingWPM int
+}
+
+func NewRepostUsecase(repo *repositories.RepostRepository, postRepo *repositories.PostRepository, blocks *BlockUsecase, readingWPM int) *RepostUsecase {
+	return &RepostUsecase{repo: repo, postRepo: postRepo, blocks: blocks, readingWPM: readingWPM}
+}
+
+// CreateRepost comparte la publicación indicada en nombre del usuario. Retorna ErrBlocked si
+// hay un bloqueo entre el usuario y el autor.
+func (u *RepostUsecase) CreateRepost(ctx context.Context, userID, postID, comment string) (*models.Repost, error) {
+	if userID == "" || postID == "" {
+		return nil, errors.New("usuario y publicación son obligatorios")
+	}
+	post, err := u.postRepo.GetByID(ctx, postID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := u.blocks.CheckInteraction(ctx, userID, post.AuthorID); err != nil {
+		return nil, err
+	}
+
+	repost := &models.Repost{
+		UserID:         userID,
+		OriginalPostID: postID,
+		Comment:        comment,
+		CreatedAt:      time.Now(),
+	}
+	if err := u.repo.Create(ctx, repost); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	return repost, nil
+}
+
+// GetUserReposts retorna los reposts del usuario con el contenido actual de cada publicación original.
+// Los reposts cuya publicación original ya no existe o no es pública se omiten.
+func (u *RepostUsecase) GetUserReposts(ctx context.Context, userID string) ([]*models.Repost, error) {
+	reposts, err := u.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(reposts))
+	for _, rp := range reposts {
+		ids = append(ids, rp.OriginalPostID)
+	}
+	originals, err := u.postRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Repost, 0, len(reposts))
+	for _, rp := range reposts {
+		original, ok := originals[rp.OriginalPostID]
+		if !ok || !canView(original, "") {
+			continue
+		}
+		presentPosts(u.readingWPM, original)
+		rp.OriginalPost = original
+		result = append(result, rp)
+	}
+	return result, nil
+}
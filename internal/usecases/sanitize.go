@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// SanitizeText elimina los bytes nulos, los demás caracteres de control (salvo salto de línea
+// y tabulación) y las secuencias UTF-8 inválidas, que corrompen lo guardado y rompen a los
+// consumidores del JSON. Los saltos "\r\n" quedan como "\n".
+func SanitizeText(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// sanitizePost limpia el título y el contenido de la publicación y valida los campos que
+// exige su tipo, por lo que un texto que solo tenía caracteres de control cuenta como vacío.
+func sanitizePost(p *models.Post) error {
+	p.Title = SanitizeText(p.Title)
+	p.Content = SanitizeText(p.Content)
+	return ValidatePostType(p)
+}
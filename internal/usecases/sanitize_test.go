@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"NUL", "ho\x00la", "hola"},
+		{"escape ANSI", "\x1b[31mrojo\x1b[0m", "[31mrojo[0m"},
+		{"DEL", "borra\x7fr", "borrar"},
+		{"C1", "a\u0085b\u009bc", "abc"},
+		{"retorno de carro", "línea\r\nsiguiente", "línea\nsiguiente"},
+		{"conserva salto de línea y tabulación", "uno\n\tdos", "uno\n\tdos"},
+		{"UTF-8 inválido", "ca\xffsa", "casa"},
+		{"texto normal", "¡Hola, mundo! 👋", "¡Hola, mundo! 👋"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeText(tt.text); got != tt.want {
+				t.Errorf("SanitizeText(%q) = %q, se esperaba %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizePostRejectsControlOnlyTitle(t *testing.T) {
+	p := &models.Post{Title: "\x00\x1b\x7f", Content: "contenido"}
+
+	var validationErr *PostValidationError
+	if err := sanitizePost(p); !errors.As(err, &validationErr) {
+		t.Fatalf("sanitizePost() = %v, se esperaba un *PostValidationError", err)
+	}
+	if p.Title != "" {
+		t.Errorf("Title = %q, se esperaba vacío", p.Title)
+	}
+}
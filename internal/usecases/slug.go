@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+// MaxSlugLength es el largo máximo de un slug.
+const MaxSlugLength = 80
+
+// slugAttempts es cuántos slugs generados se prueban antes de rendirse.
+const slugAttempts = 5
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// reservedSlugs no se asignan a publicaciones para que no choquen con rutas existentes o
+// previstas bajo /posts.
+var reservedSlugs = map[string]bool{
+	"search":         true,
+	"trending":       true,
+	"random":         true,
+	"suggest":        true,
+	"slug-available": true,
+	"import":         true,
+	"stream":         true,
+	"clear-flags":    true,
+	"review-queue":   true,
+	"archive":        true,
+	"tags":           true,
+	"top":            true,
+	"new":            true,
+	"edit":           true,
+}
+
+// NormalizeSlug lleva un slug escrito por el usuario a su forma canónica: minúsculas, sin
+// tildes y con los espacios y guiones bajos convertidos en guiones simples. No elimina otros
+// caracteres; ValidateSlug los rechaza.
+func NormalizeSlug(slug string) string {
+	slug, _, _ = transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), slug)
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	slug = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '_' {
+			return '-'
+		}
+		return r
+	}, slug)
+	return strings.Trim(collapseHyphens(slug), "-")
+}
+
+// Slugify genera un slug a partir del título, normalizándolo como NormalizeSlug y
+// reemplazando por guiones los caracteres que no son letras o dígitos.
+func Slugify(title string) string {
+	slug := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, NormalizeSlug(title))
+	slug = strings.Trim(collapseHyphens(slug), "-")
+
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	if slug == "" {
+		return "post"
+	}
+	return slug
+}
+
+// ValidateSlug retorna ErrInvalidSlug si el slug, ya normalizado, tiene caracteres distintos
+// de letras, dígitos y guiones o supera MaxSlugLength, y ErrSlugReserved si es una palabra
+// reservada.
+func ValidateSlug(slug string) error {
+	if len(slug) > MaxSlugLength || !slugPattern.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	if reservedSlugs[slug] {
+		return ErrSlugReserved
+	}
+	return nil
+}
+
+// SlugAvailable normaliza slug como al crear una publicación e indica si está libre. Retorna
+// el slug normalizado, que es el que se guardaría, y ErrInvalidSlug si no es válido. Los slugs
+// reservados se reportan como no disponibles.
+func (u *PostUsecase) SlugAvailable(ctx context.Context, slug string) (string, bool, error) {
+	slug = NormalizeSlug(slug)
+	switch err := ValidateSlug(slug); {
+	case errors.Is(err, ErrSlugReserved):
+		return slug, false, nil
+	case err != nil:
+		return slug, false, err
+	}
+
+	inUse, err := u.repo.SlugInUse(ctx, slug)
+	if err != nil {
+		return slug, false, err
+	}
+	return slug, !inUse, nil
+}
+
+func collapseHyphens(s string) string {
+	for strings.Contains(s, "--") {
+		s = strings.ReplaceAll(s, "--", "-")
+	}
+	return s
+}
+
+// createWithSlug guarda la publicación con el slug elegido por el autor, si lo hay, o con uno
+// generado desde el título. Si el slug del autor ya está ocupado retorna ErrSlugTaken cuando
+// rejectSlugConflicts está activo y, si no, usa uno generado.
+func (u *PostUsecase) createWithSlug(ctx context.Context, p *models.Post) error {
+	if p.Slug != "" {
+		err := u.repo.Create(ctx, p)
+		if !errors.Is(err, repositories.ErrAlreadyExists) {
+			return err
+		}
+		if u.rejectSlugConflicts {
+			return ErrSlugTaken
+		}
+	}
+
+	base := Slugify(p.Title)
+	for attempt := 1; attempt <= slugAttempts; attempt++ {
+		p.Slug = slugCandidate(base, attempt)
+		if reservedSlugs[p.Slug] {
+			continue
+		}
+		err := u.repo.Create(ctx, p)
+		if !errors.Is(err, repositories.ErrAlreadyExists) {
+			return err
+		}
+	}
+	return fmt.Errorf("no se encontró un slug libre para %q", base)
+}
+
+// slugCandidate retorna el slug a probar en cada intento: el base, luego con sufijos -2 y -3,
+// y después con un sufijo aleatorio.
+func slugCandidate(base string, attempt int) string {
+	var suffix string
+	switch {
+	case attempt == 1:
+		return base
+	case attempt <= 3:
+		suffix = fmt.Sprintf("-%d", attempt)
+	default:
+		b := make([]byte, 3)
+		_, _ = rand.Read(b)
+		suffix = "-" + hex.EncodeToString(b)
+	}
+	return strings.TrimRight(base[:min(len(base), MaxSlugLength-len(suffix))], "-") + suffix
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxTagsPerPost = 10
+	maxTagLength   = 30
+)
+
+// CanonicalTags limpia espacios, pasa a minúsculas, elimina duplicados y etiquetas vacías
+// y ordena el resultado alfabéticamente. Es la forma en que las etiquetas se guardan y se
+// retornan, para que todos los clientes las muestren en el mismo orden.
+func CanonicalTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	canonical := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		t := strings.ToLower(strings.TrimSpace(tag))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		canonical = append(canonical, t)
+	}
+
+	sort.Strings(canonical)
+	return canonical
+}
+
+// NormalizeTags retorna las etiquetas en su forma canónica (ver CanonicalTags).
+// Retorna ErrInvalidTags si alguna etiqueta excede el largo máximo o hay demasiadas.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := CanonicalTags(tags)
+
+	for _, t := range normalized {
+		if len([]rune(t)) > maxTagLength {
+			return nil, fmt.Errorf("%w: %q supera los %d caracteres", ErrInvalidTags, t, maxTagLength)
+		}
+	}
+	if len(normalized) > maxTagsPerPost {
+		return nil, fmt.Errorf("%w: máximo %d etiquetas por publicación", ErrInvalidTags, maxTagsPerPost)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+func TestCanonicalTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"vacío", nil, []string{}},
+		{"ordena", []string{"go", "api", "firebase"}, []string{"api", "firebase", "go"}},
+		{"duplicados", []string{"go", "api", "go"}, []string{"api", "go"}},
+		{"mayúsculas", []string{"Go", "GO", "go"}, []string{"go"}},
+		{"espacios y vacías", []string{"  Go ", "", "   ", "api"}, []string{"api", "go"}},
+		{"mayúsculas y duplicados mezclados", []string{"Backend", "api", "BACKEND", " Api "}, []string{"api", "backend"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalTags(tt.tags); !slices.Equal(got, tt.want) {
+				t.Errorf("CanonicalTags(%q) = %q, se esperaba %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagsLimits(t *testing.T) {
+	tooMany := make([]string, 0, maxTagsPerPost+1)
+	for i := range maxTagsPerPost + 1 {
+		tooMany = append(tooMany, string(rune('a'+i)))
+	}
+	// los duplicados se quitan antes de contar
+	withDuplicates := append(slices.Clone(tooMany[:maxTagsPerPost]), "A", "B")
+
+	if _, err := NormalizeTags(tooMany); !errors.Is(err, ErrInvalidTags) {
+		t.Errorf("%d etiquetas: error = %v, se esperaba ErrInvalidTags", len(tooMany), err)
+	}
+	if got, err := NormalizeTags(withDuplicates); err != nil || len(got) != maxTagsPerPost {
+		t.Errorf("NormalizeTags(%q) = %q, %v", withDuplicates, got, err)
+	}
+	if _, err := NormalizeTags([]string{strings.Repeat("x", maxTagLength+1)}); !errors.Is(err, ErrInvalidTags) {
+		t.Errorf("etiqueta larga: error = %v, se esperaba ErrInvalidTags", err)
+	}
+}
+
+func TestPresentPostsCanonicalizesStoredTags(t *testing.T) {
+	// etiquetas guardadas antes de normalizarlas al escribir
+	p := &models.Post{Tags: []string{"Go", "api", "go", " API"}}
+	presentPosts(200, p)
+
+	if want := []string{"api", "go"}; !slices.Equal(p.Tags, want) {
+		t.Errorf("Tags = %q, se esperaba %q", p.Tags, want)
+	}
+}
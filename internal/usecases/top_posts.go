@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+const (
+	// topPostsCacheTTL define cuánto tiempo se reutiliza el ranking de cada periodo, que requiere
+	// leer todos los likes del periodo.
+	topPostsCacheTTL = 5 * time.Minute
+	// maxTopPosts es el máximo de publicaciones que se guardan en el ranking de un periodo.
+	maxTopPosts = 500
+)
+
+// TopPosts retorna una página de las publicaciones públicas con más likes dados dentro de
+// period, de la que más recibió a la que menos, junto con el total. A diferencia de ordenar por
+// el contador likes, que acumula desde siempre, solo cuentan los likes de ese periodo. Los
+// empates se ordenan por likes totales y luego por fecha. El ranking de cada periodo se reutiliza
+// por 5 minutos y guarda hasta maxTopPosts publicaciones; las de autores con los que viewerID
+// tiene un bloqueo se quitan al responder, antes de paginar.
+func (u *PostUsecase) TopPosts(ctx context.Context, period time.Duration, limit, offset int, viewerID string) ([]*models.Post, int64, error) {
+	key := period.String()
+	ranking, ok := u.topPosts.Get(key)
+	if !ok {
+		var err error
+		if ranking, err = u.rankTopPosts(ctx, time.Now().Add(-period)); err != nil {
+			return nil, 0, err
+		}
+		u.topPosts.Set(key, ranking)
+	}
+	ranking, err := u.withoutHiddenAuthors(ctx, ranking, viewerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(ranking))
+	ranking = ranking[min(offset, len(ranking)):]
+	ranking = ranking[:min(limit, len(ranking))]
+
+	// copias, para que ajustar la página no modifique el ranking guardado
+	posts := make([]*models.Post, 0, len(ranking))
+	for _, p := range ranking {
+		post := *p
+		posts = append(posts, &post)
+	}
+	presentPosts(u.readingWPM, posts...)
+	for _, p := range posts {
+		p.Views += int(u.views.Pending(p.ID))
+	}
+	return posts, total, nil
+}
+
+// rankTopPosts ordena las publicaciones públicas por los likes recibidos desde since.
+func (u *PostUsecase) rankTopPosts(ctx context.Context, since time.Time) ([]*models.Post, error) {
+	counts, err := u.likes.CountLikesSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if counts[ids[i]] != counts[ids[j]] {
+			return counts[ids[i]] > counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	// margen para las que resulten no públicas o eliminadas
+	ids = ids[:min(len(ids), 2*maxTopPosts)]
+
+	found, err := u.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]*models.Post, 0, len(found))
+	for _, p := range found {
+		posts = append(posts, p)
+	}
+	posts = visiblePosts(posts, "")
+
+	sort.Slice(posts, func(i, j int) bool {
+		a, b := posts[i], posts[j]
+		if counts[a.ID] != counts[b.ID] {
+			return counts[a.ID] > counts[b.ID]
+		}
+		if a.Likes != b.Likes {
+			return a.Likes > b.Likes
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return posts[:min(len(posts), maxTopPosts)], nil
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// trendingTagsCacheTTL define cuánto tiempo se reutiliza el ranking de etiquetas de una ventana.
+const trendingTagsCacheTTL = time.Minute
+
+// TrendingTags retorna hasta limit etiquetas ordenadas por cuántas publicaciones públicas
+// creadas dentro de window las usan, de la más usada a la menos usada; los empates se ordenan
+// alfabéticamente. El ranking de cada ventana se reutiliza por un minuto.
+func (u *PostUsecase) TrendingTags(ctx context.Context, window time.Duration, limit int) ([]models.TagCount, error) {
+	key := window.String()
+	ranking, ok := u.trendingTags.Get(key)
+	if !ok {
+		posts, err := u.repo.GetTagsSince(ctx, time.Now().Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		ranking = rankTags(visiblePosts(posts, ""))
+		u.trendingTags.Set(key, ranking)
+	}
+
+	if len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+	return ranking, nil
+}
+
+// rankTags cuenta en cuántas publicaciones aparece cada etiqueta y las ordena por ese número.
+func rankTags(posts []*models.Post) []models.TagCount {
+	counts := make(map[string]int)
+	for _, p := range posts {
+		for _, tag := range CanonicalTags(p.Tags) {
+			counts[tag]++
+		}
+	}
+
+	ranking := make([]models.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		ranking = append(ranking, models.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Count != ranking[j].Count {
+			return ranking[i].Count > ranking[j].Count
+		}
+		return ranking[i].Tag < ranking[j].Tag
+	})
+	return ranking
+}
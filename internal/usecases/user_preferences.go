@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+// GetPreferences retorna las preferencias del usuario, sin filtros si nunca las configuró.
+// Se guardan en caché por un periodo corto, ya que se leen en cada listado del usuario.
+// Retorna ErrUserNotFound si el usuario no existe.
+func (u *UserUsecase) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	if prefs, ok := u.preferences.Get(userID); ok {
+		return prefs, nil
+	}
+
+	prefs, err := u.repo.GetPreferences(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefs = normalizePreferences(prefs)
+	u.preferences.Set(userID, prefs)
+	return prefs, nil
+}
+
+// UpdatePreferences reemplaza las preferencias del usuario y retorna las guardadas, con las
+// etiquetas en su forma canónica y sin usuarios repetidos ni el propio usuario.
+// Retorna ErrUserNotFound si el usuario no existe.
+func (u *UserUsecase) UpdatePreferences(ctx context.Context, userID string, prefs *models.UserPreferences) (*models.UserPreferences, error) {
+	prefs = normalizePreferences(prefs)
+	prefs.MutedUsers = removeString(prefs.MutedUsers, userID)
+
+	err := u.repo.UpdatePreferences(ctx, userID, prefs)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.preferences.Set(userID, prefs)
+	return prefs, nil
+}
+
+// normalizePreferences retorna una copia con las etiquetas canónicas y los usuarios sin
+// repetir, usando listas vacías en lugar de nil.
+func normalizePreferences(prefs *models.UserPreferences) *models.UserPreferences {
+	users := make([]string, 0, len(prefs.MutedUsers))
+	seen := make(map[string]bool, len(prefs.MutedUsers))
+	for _, id := range prefs.MutedUsers {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		users = append(users, id)
+	}
+	return &models.UserPreferences{
+		MutedTags:   CanonicalTags(prefs.MutedTags),
+		MutedUsers:  users,
+		DefaultSort: prefs.DefaultSort,
+	}
+}
+
+// removeString retorna values sin las apariciones de s.
+func removeString(values []string, s string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if v != s {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// maxMutedUsers es el máximo de usuarios que alguien puede silenciar.
+const maxMutedUsers = 100
+
+// MuteUser silencia a mutedID para userID: sus publicaciones dejan de aparecer en los listados
+// de userID. Es privado, mutedID no recibe ningún aviso. Silenciar a alguien ya silenciado no
+// cambia nada. Retorna ErrSelfMute si son el mismo usuario, ErrUserNotFound si alguno no existe
+// y ErrTooManyMuted si ya se alcanzó el máximo.
+func (u *UserUsecase) MuteUser(ctx context.Context, userID, mutedID string) error {
+	if userID == mutedID {
+		return ErrSelfMute
+	}
+	if _, err := u.repo.GetUserByID(ctx, mutedID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	prefs, err := u.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(prefs.MutedUsers, mutedID) {
+		return nil
+	}
+	if len(prefs.MutedUsers) >= maxMutedUsers {
+		return ErrTooManyMuted
+	}
+	return u.setMuted(ctx, userID, mutedID, true)
+}
+
+// UnmuteUser deja de silenciar a mutedID para userID. No falla si no estaba silenciado.
+// Retorna ErrUserNotFound si userID no existe.
+func (u *UserUsecase) UnmuteUser(ctx context.Context, userID, mutedID string) error {
+	return u.setMuted(ctx, userID, mutedID, false)
+}
+
+func (u *UserUsecase) setMuted(ctx context.Context, userID, mutedID string, muted bool) error {
+	err := u.repo.SetMuted(ctx, userID, mutedID, muted)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	u.preferences.Delete(userID)
+	return nil
+}
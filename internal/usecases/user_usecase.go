@@ -3,16 +3,33 @@ package usecases
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
+	"github.com/JuanPidarraga/talkus-backend/internal/cache"
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
 	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
 )
 
+// profileCacheTTL define cuánto tiempo se reutiliza un perfil ya calculado.
+const profileCacheTTL = 30 * time.Second
+
 type UserUsecase struct {
-	repo *repositories.UserRepository
+	repo        *repositories.UserRepository
+	postRepo    *repositories.PostRepository
+	profiles    *cache.TTLCache[*models.UserProfile]
+	likes       *cache.TTLCache[int64]
+	preferences *cache.TTLCache[*models.UserPreferences]
 }
 
-func NewUserUsecase(repo *repositories.UserRepository) *UserUsecase {
-	return &UserUsecase{repo: repo}
+func NewUserUsecase(repo *repositories.UserRepository, postRepo *repositories.PostRepository) *UserUsecase {
+	return &UserUsecase{
+		repo:        repo,
+		postRepo:    postRepo,
+		profiles:    cache.NewTTLCache[*models.UserProfile](profileCacheTTL),
+		likes:       cache.NewTTLCache[int64](profileCacheTTL),
+		preferences: cache.NewTTLCache[*models.UserPreferences](profileCacheTTL),
+	}
 }
 
 // GetUser ejecuta la lógica para obtener un usuario por ID.
@@ -27,3 +44,210 @@ func (u *UserUsecase) GetUser(ctx context.Context, userID string) (map[string]in
 	}
 	return user, nil
 }
+
+// GetProfile compone el perfil público del usuario con sus estadísticas agregadas.
+// El resultado se guarda en caché por un periodo corto para evitar repetir las agregaciones.
+func (u *UserUsecase) GetProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	if userID == "" {
+		return nil, errors.New("falta el parámetro 'id'")
+	}
+	if profile, ok := u.profiles.Get(userID); ok {
+		return profile, nil
+	}
+
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	postCount, err := u.postRepo.CountByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	likes, err := u.postRepo.SumLikesByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &models.UserProfile{
+		UID:            userID,
+		Username:       stringField(user, "username"),
+		PostCount:      postCount,
+		LikesReceived:  likes,
+		Karma:          intField(user, "karma"),
+		FollowersCount: intField(user, "followersCount"),
+		FollowingCount: intField(user, "followingCount"),
+		JoinedAt:       models.Timestamp(timeField(user, "createdAt")),
+	}
+	u.profiles.Set(userID, profile)
+	return profile, nil
+}
+
+// GetLikesReceived retorna la suma de likes de todas las publicaciones del usuario.
+// Los usuarios sin publicaciones tienen 0 likes.
+func (u *UserUsecase) GetLikesReceived(ctx context.Context, userID string) (int64, error) {
+	if userID == "" {
+		return 0, errors.New("falta el parámetro 'id'")
+	}
+	if likes, ok := u.likes.Get(userID); ok {
+		return likes, nil
+	}
+
+	likes, err := u.postRepo.SumLikesByAuthor(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	u.likes.Set(userID, likes)
+	return likes, nil
+}
+
+// GetAnalytics resume las interacciones con las publicaciones de userID creadas en [from, to),
+// con los totales del periodo y el detalle de la página indicada. Retorna también cuántas
+// publicaciones hay en el periodo. from y to en cero no limitan.
+func (u *UserUsecase) GetAnalytics(ctx context.Context, userID string, from, to time.Time, limit, offset int) (*models.UserAnalytics, int64, error) {
+	if userID == "" {
+		return nil, 0, errors.New("falta el parámetro 'id'")
+	}
+
+	posts, err := u.postRepo.GetByAuthorBetween(ctx, userID, from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	analytics := &models.UserAnalytics{UserID: userID, Totals: models.EngagementTotals{Posts: len(posts)}}
+	for _, p := range posts {
+		analytics.Totals.Views += p.Views
+		analytics.Totals.Likes += p.Likes
+		analytics.Totals.Dislikes += p.Dislikes
+		analytics.Totals.Reposts += p.RepostCount
+	}
+
+	start := min(offset, len(posts))
+	end := min(start+limit, len(posts))
+	analytics.Posts = make([]models.PostEngagement, 0, end-start)
+	for _, p := range posts[start:end] {
+		analytics.Posts = append(analytics.Posts, models.PostEngagement{
+			PostID:      p.ID,
+			Title:       p.Title,
+			Slug:        p.Slug,
+			CreatedAt:   models.Timestamp(p.CreatedAt),
+			Views:       p.Views,
+			Likes:       p.Likes,
+			Dislikes:    p.Dislikes,
+			RepostCount: p.RepostCount,
+			Reactions:   p.Reactions,
+		})
+	}
+	return analytics, int64(len(posts)), nil
+}
+
+// RecomputeKarma recalcula desde cero y guarda el karma de todos los usuarios, por ejemplo si
+// un cambio de autor en lote dejó alguno desfasado. Retorna cuántos usuarios se actualizaron.
+func (u *UserUsecase) RecomputeKarma(ctx context.Context) (int, error) {
+	ids, err := u.repo.ListIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, id := range ids {
+		likes, dislikes, err := u.postRepo.SumInteractionsByAuthor(ctx, id)
+		if err != nil {
+			return updated, err
+		}
+		if err := u.repo.UpdateKarma(ctx, id, models.ComputeKarma(likes, dislikes)); err != nil {
+			return updated, err
+		}
+		u.profiles.Delete(id)
+		updated++
+	}
+
+	log.Printf("Karma recalculado para %d usuarios", updated)
+	return updated, nil
+}
+
+// GetUsers retorna los datos públicos de los usuarios indicados, en el orden pedido y sin
+// repetidos. Los que no existen se omiten.
+func (u *UserUsecase) GetUsers(ctx context.Context, userIDs []string) ([]*models.PublicUser, error) {
+	ids := make([]string, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	docs, err := u.repo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*models.PublicUser, 0, len(docs))
+	for _, id := range ids {
+		if data, ok := docs[id]; ok {
+			users = append(users, publicUser(id, data))
+		}
+	}
+	return users, nil
+}
+
+// EmbedAuthors completa Author en cada publicación con una sola consulta de usuarios. Las
+// publicaciones cuyo autor ya no existe quedan sin Author.
+func (u *UserUsecase) EmbedAuthors(ctx context.Context, posts []*models.Post) error {
+	ids := make([]string, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.AuthorID)
+	}
+	users, err := u.GetUsers(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	authors := make(map[string]*models.PostAuthor, len(users))
+	for _, user := range users {
+		authors[user.UID] = &models.PostAuthor{ID: user.UID, DisplayName: user.Username, PhotoURL: user.PhotoURL}
+	}
+	for _, p := range posts {
+		p.Author = authors[p.AuthorID]
+	}
+	return nil
+}
+
+// publicUser arma los datos públicos del usuario a partir de su documento.
+func publicUser(id string, data map[string]interface{}) *models.PublicUser {
+	return &models.PublicUser{
+		UID:      id,
+		Username: stringField(data, "username"),
+		PhotoURL: stringField(data, "photoURL"),
+		Karma:    intField(data, "karma"),
+		JoinedAt: models.Timestamp(timeField(data, "createdAt")),
+	}
+}
+
+// stringField lee un campo de texto del documento, retornando "" si no existe.
+func stringField(data map[string]interface{}, key string) string {
+	v, _ := data[key].(string)
+	return v
+}
+
+// intField lee un contador del documento, retornando 0 si no existe.
+func intField(data map[string]interface{}, key string) int64 {
+	switch v := data[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// timeField lee una fecha del documento, retornando el valor cero si no existe.
+func timeField(data map[string]interface{}, key string) time.Time {
+	v, _ := data[key].(time.Time)
+	return v
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/cache"
+	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
+)
+
+// ViewStore persiste los incrementos del contador de vistas.
+type ViewStore interface {
+	IncrementViews(ctx context.Context, id string, n int64) error
+}
+
+// ViewCounter cuenta las vistas de las publicaciones. Las vistas repetidas de un mismo
+// visitante dentro de la ventana de debounce se ignoran, y los incrementos se acumulan en
+// memoria hasta el siguiente Flush para no escribir en Firestore en cada lectura. Las
+// vistas acumuladas se pierden si el servidor se detiene antes de escribirlas.
+type ViewCounter struct {
+	store ViewStore
+	seen  *cache.TTLCache[bool]
+
+	mu      sync.Mutex
+	pending map[string]int64
+}
+
+func NewViewCounter(store ViewStore, debounce time.Duration) *ViewCounter {
+	return &ViewCounter{
+		store:   store,
+		seen:    cache.NewTTLCache[bool](debounce),
+		pending: make(map[string]int64),
+	}
+}
+
+// Record registra una vista de viewerKey a la publicación postID. Retorna false si el
+// visitante ya la había visto dentro de la ventana de debounce.
+func (c *ViewCounter) Record(postID, viewerKey string) bool {
+	if !c.seen.SetIfAbsent(postID+"|"+viewerKey, true) {
+		return false
+	}
+
+	c.mu.Lock()
+	c.pending[postID]++
+	c.mu.Unlock()
+	return true
+}
+
+// Pending retorna las vistas de postID que aún no se han escrito en Firestore.
+func (c *ViewCounter) Pending(postID string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending[postID]
+}
+
+// Flush escribe las vistas acumuladas. Los incrementos que fallan se conservan para el
+// siguiente intento.
+func (c *ViewCounter) Flush(ctx context.Context) {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = make(map[string]int64, len(batch))
+	c.mu.Unlock()
+
+	for postID, n := range batch {
+		err := c.store.IncrementViews(ctx, postID, n)
+		if err == nil || errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		log.Printf("Error guardando vistas de %s: %v", postID, err)
+		c.mu.Lock()
+		c.pending[postID] += n
+		c.mu.Unlock()
+	}
+}
+
+// Run ejecuta Flush cada interval hasta que ctx se cancela, con una última escritura al salir.
+func (c *ViewCounter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Flush(ctx)
+		case <-ctx.Done():
+			c.Flush(context.Background())
+			return
+		}
+	}
+}
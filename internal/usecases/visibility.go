@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+
+	"github.com/JuanPidarraga/talkus-backend/internal/models"
+)
+
+// canView indica si viewerID puede ver la publicación; viewerID vacío es un visitante anónimo.
+// Las publicaciones sin visibilidad guardada son públicas. Mientras no exista un registro de
+// seguidores, las publicaciones para seguidores solo las ve su autor, igual que las privadas.
+func canView(p *models.Post, viewerID string) bool {
+	switch p.Visibility {
+	case "", models.VisibilityPublic:
+		return true
+	default:
+		return viewerID != "" && viewerID == p.AuthorID
+	}
+}
+
+// visiblePosts retorna, en el mismo orden, las publicaciones que viewerID puede ver.
+func visiblePosts(posts []*models.Post, viewerID string) []*models.Post {
+	visible := posts[:0]
+	for _, p := range posts {
+		if canView(p, viewerID) {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
+
+// withoutHiddenAuthors retorna, en el mismo orden, las publicaciones de posts cuyo autor no tiene
+// un bloqueo con viewerID. Es el filtro de bloqueos de todas las lecturas de publicaciones. Si
+// quita alguna retorna un slice nuevo, para no modificar los que vienen de una caché.
+func (u *PostUsecase) withoutHiddenAuthors(ctx context.Context, posts []*models.Post, viewerID string) ([]*models.Post, error) {
+	if u.authorBlocks == nil || viewerID == "" || len(posts) == 0 {
+		return posts, nil
+	}
+	hidden, err := u.authorBlocks.Hidden(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(hidden) == 0 {
+		return posts, nil
+	}
+
+	kept := make([]*models.Post, 0, len(posts))
+	for _, p := range posts {
+		if !slices.Contains(hidden, p.AuthorID) {
+			kept = append(kept, p)
+		}
+	}
+	return kept, nil
+}
+
+// hiddenFrom indica si un bloqueo entre viewerID y el autor le oculta la publicación.
+func (u *PostUsecase) hiddenFrom(ctx context.Context, p *models.Post, viewerID string) (bool, error) {
+	kept, err := u.withoutHiddenAuthors(ctx, []*models.Post{p}, viewerID)
+	return len(kept) == 0, err
+}
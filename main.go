@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
@@ -8,20 +10,34 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"github.com/JuanPidarraga/talkus-backend/config"
 	_ "github.com/JuanPidarraga/talkus-backend/docs"
+	"github.com/JuanPidarraga/talkus-backend/internal/buildinfo"
 	"github.com/JuanPidarraga/talkus-backend/internal/controllers"
+	"github.com/JuanPidarraga/talkus-backend/internal/features"
 	"github.com/JuanPidarraga/talkus-backend/internal/handlers"
 	"github.com/JuanPidarraga/talkus-backend/internal/middleware"
 	"github.com/JuanPidarraga/talkus-backend/internal/repositories"
 	"github.com/JuanPidarraga/talkus-backend/internal/service"
+	"github.com/JuanPidarraga/talkus-backend/internal/tracing"
 	"github.com/JuanPidarraga/talkus-backend/internal/usecases"
 	"github.com/cloudinary/cloudinary-go/v2"
 )
 
 func main() {
 
+	build := buildinfo.Get()
+	log.Printf("Iniciando talkus-backend %s (commit %s)", build.Version, build.Commit)
+
+	// Trazado OpenTelemetry, desactivado salvo TRACING_ENABLED=true
+	shutdownTracing, err := tracing.Setup(context.Background(), config.LoadTracingConfig())
+	if err != nil {
+		log.Fatalf("Error iniciando el trazado: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Inicializar Firebase (con credenciales definidas en la variable de entorno FIREBASE_CREDENTIALS_PATH)
 	firebaseApp, err := config.InitFirebase()
 	if err != nil {
@@ -39,18 +55,95 @@ func main() {
 		log.Fatalf("Error iniciando Cloudinary: %v", err)
 	}
 
+	uploadCfg := config.LoadUploadConfig()
+	imageUploader := service.NewCloudinaryUploader(cld, uploadCfg.UploadTimeout, uploadCfg.AllowedFolders)
+	featureFlags := features.New(features.EnvSource{})
+
 	authService := service.NewAuthService(firebaseApp)
 	authHandler := handlers.NewAuthHandler(authService)
 	authMiddleware := middleware.NewAuthMiddleware(authService)
 
+	maintenanceCfg := config.LoadMaintenanceConfig()
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceState{
+		Enabled: maintenanceCfg.Enabled,
+		Block:   middleware.MaintenanceBlock(maintenanceCfg.Block),
+	}, maintenanceCfg.AllowedIPs)
+	// la ruta de administración debe seguir disponible para poder desactivar el mantenimiento
+	maintenance.Exempt("/admin/maintenance")
+
 	userRepo := repositories.NewUserRepository(firebaseApp.Firestore)
-	userUsecase := usecases.NewUserUsecase(userRepo)
+	postRepo := repositories.NewPostRepository(firebaseApp.Firestore)
+
+	userUsecase := usecases.NewUserUsecase(userRepo, postRepo)
 	userController := controllers.NewUserController(userUsecase)
+	blockUsecase := usecases.NewBlockUsecase(repositories.NewBlockRepository(firebaseApp.Firestore), userRepo)
+	blockController := controllers.NewBlockController(blockUsecase)
+
+	// Notification layer
+	notificationRepo := repositories.NewNotificationRepository(firebaseApp.Firestore)
+	mentionWebhook := service.NewMentionWebhook(os.Getenv("MENTION_WEBHOOK_URL"))
+	notificationUsecase := usecases.NewNotificationUsecase(notificationRepo, mentionWebhook)
+	notificationController := controllers.NewNotificationController(notificationUsecase)
 
 	// Post layer
-	postRepo := repositories.NewPostRepository(firebaseApp.Firestore)
-	postUsecase := usecases.NewPostUsecase(postRepo)
-	postController := controllers.NewPostController(postUsecase, cld)
+	viewCfg := config.LoadViewConfig()
+	viewCounter := usecases.NewViewCounter(postRepo, viewCfg.DebounceWindow)
+	go viewCounter.Run(context.Background(), viewCfg.FlushInterval)
+
+	postCfg := config.LoadPostConfig()
+	linkPreviews := service.NewLinkPreviewFetcher(postCfg.LinkPreviewTimeout)
+	blockedDomains := usecases.NewDomainBlocklist(postCfg.BlockedDomains, postCfg.BlockedDomainsMode)
+	log.Printf("Dominios bloqueados en publicaciones: %d (modo %s)", blockedDomains.Len(), postCfg.BlockedDomainsMode)
+	reactionRepo := repositories.NewReactionRepository(firebaseApp.Firestore)
+	postUsecase := usecases.NewPostUsecase(postRepo, featureFlags, usecases.PostUsecaseOptions{
+		Users:               userRepo,
+		Notifier:            notificationUsecase,
+		Previews:            linkPreviews,
+		Likes:               reactionRepo,
+		Views:               viewCounter,
+		ReadingWPM:          postCfg.ReadingWPM,
+		RejectSlugConflicts: postCfg.RejectSlugConflicts,
+		DailyPostLimit:      postCfg.DailyPostLimit,
+		Blocked:             blockedDomains,
+		AuthorBlocks:        blockUsecase,
+	})
+	postExpirer := usecases.NewPostExpirer(postRepo, imageUploader)
+	go postExpirer.Run(context.Background(), postCfg.ExpirySweepInterval)
+
+	imageRetrier := usecases.NewImageRetrier(postRepo, imageUploader)
+	go imageRetrier.Run(context.Background(), uploadCfg.RetryInterval)
+	postController := controllers.NewPostController(postUsecase, userUsecase, blockUsecase, imageUploader, imageRetrier, uploadCfg, featureFlags)
+
+	assetJanitor := service.NewAssetJanitor(imageUploader, imageUploader)
+	go assetJanitor.Run(context.Background(), service.PreviewFolder, uploadCfg.PreviewTTL, uploadCfg.PreviewSweepInterval)
+	imageController := controllers.NewImageController(imageUploader, assetJanitor, uploadCfg)
+	infoController := controllers.NewInfoController(featureFlags, uploadCfg, postCfg.DailyPostLimit)
+
+	siteURL := os.Getenv("SITE_URL")
+	if siteURL == "" {
+		siteURL = "http://localhost:8080"
+	}
+	feedController := controllers.NewFeedController(postUsecase, siteURL)
+	shareController := controllers.NewShareController(postUsecase, siteURL, os.Getenv("OG_DEFAULT_IMAGE_URL"))
+
+	reactionController := controllers.NewReactionController(usecases.NewReactionUsecase(reactionRepo, postRepo, blockUsecase))
+
+	repostRepo := repositories.NewRepostRepository(firebaseApp.Firestore)
+	repostUsecase := usecases.NewRepostUsecase(repostRepo, postRepo, blockUsecase, postCfg.ReadingWPM)
+	repostController := controllers.NewRepostController(repostUsecase, featureFlags)
+
+	// Admin layer
+	auditRepo := repositories.NewAuditLogRepository(firebaseApp.Firestore)
+	auditUsecase := usecases.NewAuditUsecase(auditRepo)
+	auditController := controllers.NewAuditController(auditUsecase)
+
+	moderationUsecase := usecases.NewModerationUsecase(postRepo, userRepo, imageUploader, auditUsecase)
+	moderationController := controllers.NewModerationController(moderationUsecase)
+
+	webhookCfg := config.LoadWebhookConfig()
+	cloudinaryWebhookController := controllers.NewCloudinaryWebhookController(
+		service.NewNotificationVerifier(webhookCfg.CloudinarySecret, webhookCfg.CloudinarySignatureAlgorithm, webhookCfg.CloudinaryMaxAge),
+		moderationUsecase)
 
 	// Usar Gorilla Mux para definir rutas
 	router := mux.NewRouter()
@@ -58,24 +151,103 @@ func main() {
 	publicRouter := router.PathPrefix("/public").Subrouter()
 	publicRouter.HandleFunc("/register", authHandler.Register).Methods("POST")
 	publicRouter.HandleFunc("/users", userController.GetUser).Methods("GET")
+	publicRouter.HandleFunc("/users/batch", userController.GetBatch).Methods("POST")
+	publicRouter.HandleFunc("/users/{id}/profile", userController.GetProfile).Methods("GET")
+	publicRouter.HandleFunc("/users/{id}/stats/likes-received", userController.GetLikesReceived).Methods("GET")
+	publicRouter.Handle("/users/{id}/analytics", authMiddleware.Authenticate(http.HandlerFunc(userController.GetAnalytics))).Methods("GET")
+	publicRouter.Handle("/users/{id}/mute", authMiddleware.Authenticate(http.HandlerFunc(userController.Mute))).Methods("POST")
+	publicRouter.Handle("/users/{id}/mute", authMiddleware.Authenticate(http.HandlerFunc(userController.Unmute))).Methods("DELETE")
+	publicRouter.Handle("/users/{id}/block", authMiddleware.Authenticate(http.HandlerFunc(blockController.Block))).Methods("POST")
+	publicRouter.Handle("/users/{id}/block", authMiddleware.Authenticate(http.HandlerFunc(blockController.Unblock))).Methods("DELETE")
 	publicRouter.HandleFunc("/forgot-password", handlers.ForgotPasswordHandler(authService)).Methods("POST")
-	publicRouter.HandleFunc("/posts", postController.GetAll).Methods("GET")
-	publicRouter.HandleFunc("/posts", postController.Create).Methods("POST")
+	// en las lecturas de publicaciones la autenticación es opcional: permite a los autores ver
+	// sus publicaciones privadas
+	publicRouter.Handle("/posts", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.GetAll))).Methods("GET")
+	// la autenticación es opcional: identifica al autor y reconoce a los administradores
+	publicRouter.Handle("/posts", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.Create))).Methods("POST")
+	// se registra antes de /posts/{id} para que "suggest" no se tome como un ID
+	publicRouter.Handle("/posts/suggest", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.Suggest))).Methods("GET")
+	publicRouter.HandleFunc("/posts/slug-available", postController.SlugAvailable).Methods("GET")
+	publicRouter.Handle("/posts/random", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.Random))).Methods("GET")
+	publicRouter.HandleFunc("/posts/archive", postController.Archive).Methods("GET")
+	publicRouter.Handle("/posts/archive/{year:[0-9]+}/{month:[0-9]+}", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.ArchiveMonth))).Methods("GET")
+	publicRouter.Handle("/posts/top", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.Top))).Methods("GET")
+	publicRouter.Handle("/posts/tags", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.GetByTags))).Methods("GET")
+	publicRouter.Handle("/posts/{id}", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.GetByID))).Methods("GET")
+	publicRouter.Handle("/resolve", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.Resolve))).Methods("GET")
+	publicRouter.Handle("/posts/{id}/react", authMiddleware.Authenticate(http.HandlerFunc(reactionController.React))).Methods("POST")
+	publicRouter.Handle("/posts/{id}/tags", authMiddleware.Authenticate(http.HandlerFunc(postController.UpdateTags))).Methods("PUT")
+	publicRouter.Handle("/posts/{id}/og", authMiddleware.OptionalAuthenticate(http.HandlerFunc(shareController.OpenGraph))).Methods("GET")
+	publicRouter.Handle("/posts/{id}/related", authMiddleware.OptionalAuthenticate(http.HandlerFunc(postController.GetRelated))).Methods("GET")
+	publicRouter.HandleFunc("/tags/trending", postController.TrendingTags).Methods("GET")
+	publicRouter.Handle("/images/preview", authMiddleware.Authenticate(http.HandlerFunc(imageController.Preview))).Methods("POST")
+	publicRouter.HandleFunc("/config/upload", imageController.Constraints).Methods("GET")
+	publicRouter.HandleFunc("/info", infoController.Get).Methods("GET")
+	publicRouter.Handle("/feed.rss", authMiddleware.OptionalAuthenticate(http.HandlerFunc(feedController.RSS))).Methods("GET")
+	publicRouter.Handle("/feed.atom", authMiddleware.OptionalAuthenticate(http.HandlerFunc(feedController.Atom))).Methods("GET")
+	publicRouter.HandleFunc("/users/{id}/reposts", repostController.GetByUser).Methods("GET")
+	publicRouter.Handle("/users/{id}/notifications", authMiddleware.Authenticate(http.HandlerFunc(notificationController.GetByUser))).Methods("GET")
+	publicRouter.Handle("/users/{id}/notifications/unread-count", authMiddleware.Authenticate(http.HandlerFunc(notificationController.UnreadCount))).Methods("GET")
+	publicRouter.Handle("/users/{id}/notifications/{notificationId}/read", authMiddleware.Authenticate(http.HandlerFunc(notificationController.MarkRead))).Methods("POST")
+	publicRouter.Handle("/users/{id}/notifications/read-all", authMiddleware.Authenticate(http.HandlerFunc(notificationController.MarkAllRead))).Methods("POST")
+
+	// Cloudinary no envía token: la notificación se autentica con su firma
+	router.HandleFunc("/webhooks/cloudinary", cloudinaryWebhookController.Notify).Methods("POST")
 
 	protectedRouter := router.PathPrefix("/api").Subrouter()
 	protectedRouter.Use(authMiddleware.Authenticate)
 	protectedRouter.HandleFunc("/profile", authHandler.GetUserProfile)
+	protectedRouter.HandleFunc("/posts/{id}/repost", repostController.Create).Methods("POST")
+	protectedRouter.HandleFunc("/preferences", userController.GetPreferences).Methods("GET")
+	protectedRouter.HandleFunc("/preferences", userController.UpdatePreferences).Methods("PUT")
+
+	// Rutas de /admin abiertas también a moderadores. Se registran antes que adminRouter para
+	// que no les aplique su restricción a administradores.
+	requireStaff := func(h http.HandlerFunc) http.Handler {
+		return authMiddleware.Authenticate(middleware.RequireRole(middleware.RoleAdmin, middleware.RoleModerator)(h))
+	}
+	router.Handle("/admin/posts/clear-flags", requireStaff(moderationController.ClearFlags)).Methods("POST")
+	router.Handle("/admin/posts/review-queue", requireStaff(moderationController.ReviewQueue)).Methods("GET")
+
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(authMiddleware.Authenticate, middleware.RequireRole(middleware.RoleAdmin))
+	adminRouter.HandleFunc("/posts/import", postController.Import).Methods("POST")
+	adminRouter.HandleFunc("/posts/stream", postController.Stream).Methods("GET")
+	adminRouter.HandleFunc("/posts/merge", moderationController.MergePosts).Methods("POST")
+	adminRouter.HandleFunc("/posts/{id}", moderationController.ForceDeletePost).Methods("DELETE")
+	adminRouter.HandleFunc("/posts/{id}/transfer", moderationController.TransferPost).Methods("POST")
+	adminRouter.HandleFunc("/users/{id}/posts/transfer", moderationController.TransferAllPosts).Methods("POST")
+	adminRouter.HandleFunc("/audit-logs", auditController.List).Methods("GET")
+	adminRouter.HandleFunc("/users/karma/recompute", userController.RecomputeKarma).Methods("POST")
+	adminRouter.HandleFunc("/flags", handlers.FeatureFlagsHandler(featureFlags)).Methods("GET")
+	adminRouter.HandleFunc("/maintenance", handlers.MaintenanceStatusHandler(maintenance)).Methods("GET")
+	adminRouter.HandleFunc("/maintenance", handlers.MaintenanceToggleHandler(maintenance)).Methods("PUT")
+	// métricas del proceso publicadas con expvar, entre ellas slow_requests
+	adminRouter.Handle("/debug/vars", expvar.Handler()).Methods("GET")
 
 	corsOptions := cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Acccept", "Content-Type", "Authorization", "X-Requested-With"},
-		ExposedHeaders:   []string{"Content-Length", "Content-Type"},
+		AllowedHeaders:   []string{"Acccept", "Content-Type", "Authorization", "X-Requested-With", "X-Session-ID", "If-Modified-Since"},
+		ExposedHeaders:   []string{"Content-Length", "Content-Type", "Link", "X-Total-Count", "X-Request-ID", "Last-Modified"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}
 
-	handler := cors.New(corsOptions).Handler(router)
+	compressionCfg := config.LoadCompressionConfig()
+	compress := middleware.Compress(compressionCfg.MinBytes, compressionCfg.Level)
+
+	router.Use(tracing.RouteNames)
+	slowCfg := config.LoadSlowRequestConfig()
+	router.Use(middleware.SlowRequests(middleware.SlowRequestThresholds{
+		Default: slowCfg.Default,
+		Uploads: slowCfg.Uploads,
+		Admin:   slowCfg.Admin,
+	}))
+	// Recover va justo sobre el router, dentro de Compress: el 500 de un panic sale comprimido
+	// como cualquier respuesta y, si el handler ya había escrito, Recover aborta la conexión con
+	// http.ErrAbortHandler en lugar de dejarla terminar como si nada
+	handler := otelhttp.NewHandler(cors.New(corsOptions).Handler(maintenance.Middleware(compress(middleware.Recover(router)))), "http")
 	serverPort := ":8080"
 
 	router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {